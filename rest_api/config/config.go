@@ -0,0 +1,341 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"app/rest_api/logging"
+	wikiSteps "app/rest_api/wiki_steps"
+
+	"gopkg.in/yaml.v3"
+)
+
+const EnvPrefix string = "APP_" // environment variables overriding a setting are named APP_ plus the setting in upper snake case
+
+var (
+	durationType reflect.Type = reflect.TypeOf(time.Duration(0))
+	stringsType  reflect.Type = reflect.TypeOf([]string{})
+)
+
+// Config is the configuration of one environment of config.yaml, every setting can be
+// overridden by an environment variable and a CLI flag named after its yaml key
+type Config struct {
+	ContainerName       string        `yaml:"containerName"`
+	Port                int           `yaml:"port"`
+	LogLevel            string        `yaml:"logLevel"`
+	Logger              string        `yaml:"logger"`                // zerolog or zap
+	WikiHost            string        `yaml:"wikiHost"`              // scheme and host of the MediaWiki install to search
+	ArticlePath         string        `yaml:"articlePath"`           // path articles are served under
+	ApiPath             string        `yaml:"apiPath"`               // path of the MediaWiki Action API
+	CapitalLinks        bool          `yaml:"capitalLinks"`          // false for wikis with case-sensitive titles such as Wiktionary
+	BlockedTitles       []string      `yaml:"blockedTitles"`         // title prefixes never stepped through, comma separated in flags and environment variables
+	BlockedTitlePattern string        `yaml:"blockedTitlePattern"`   // titles never stepped through, empty blocks every title in a namespace
+	MaxConcurrent       int           `yaml:"maxConcurrentRequests"` // requests to the wiki in flight at once
+	RequestsPerSecond   float64       `yaml:"requestsPerSecond"`     // sustained rate of requests to the wiki, 0 disables rate limiting
+	RequestBurst        int           `yaml:"requestBurst"`          // requests allowed at once before the rate applies
+	MaxRetries          int           `yaml:"maxRetries"`            // retries of a request answered with a retryable status
+	InitialBackoff      time.Duration `yaml:"initialBackoff"`        // delay before the first retry, doubled for every retry
+	MaxBackoff          time.Duration `yaml:"maxBackoff"`
+	UserAgent           string        `yaml:"userAgent"`
+	LinkSource          string        `yaml:"linkSource"` // html, api or graph
+	GraphFile           string        `yaml:"graphFile"`  // written by import_dump from a Wikipedia dump
+	LinkCacheDir        string        `yaml:"linkCacheDir"`
+	LinkCacheTtl        time.Duration `yaml:"linkCacheTtl"`
+	MaxSteps            int           `yaml:"maxSteps"`
+	NumWorkers          int           `yaml:"numWorkers"`
+	StepTimeout         time.Duration `yaml:"stepTimeout"`
+	MaxRunningJobs      int           `yaml:"maxRunningJobs"`
+	ShutdownTimeout     time.Duration `yaml:"shutdownTimeout"` // how long in-flight requests and jobs may take to drain on shutdown
+}
+
+func Default() Config {
+	politeness := wikiSteps.DefaultPolitenessPolicy()
+	return Config{
+		ContainerName:       "learning go app",
+		Port:                8000,
+		LogLevel:            "info",
+		Logger:              "zerolog",
+		WikiHost:            "https://en.wikipedia.org",
+		ArticlePath:         "/wiki/",
+		ApiPath:             wikiSteps.WikipediaApiPath,
+		CapitalLinks:        true,
+		BlockedTitles:       []string{"Main_Page"},
+		BlockedTitlePattern: "",
+		MaxConcurrent:       politeness.MaxConcurrent,
+		RequestsPerSecond:   politeness.RequestsPerSecond,
+		RequestBurst:        politeness.Burst,
+		MaxRetries:          politeness.MaxRetries,
+		InitialBackoff:      politeness.InitialBackoff,
+		MaxBackoff:          politeness.MaxBackoff,
+		UserAgent:           politeness.UserAgent,
+		LinkSource:          "html",
+		GraphFile:           "wiki_graph.bin",
+		LinkCacheDir:        "link_cache",
+		LinkCacheTtl:        7 * 24 * time.Hour,
+		MaxSteps:            7,
+		NumWorkers:          25,
+		StepTimeout:         30 * time.Second,
+		MaxRunningJobs:      4,
+		ShutdownTimeout:     15 * time.Second,
+	}
+}
+
+// Load reads the configuration of env from the YAML file at path over the defaults, then applies
+// the environment variables found by lookupEnv and the flags defined by DefineFlags that were set
+// on flags, either of which may be nil. The result is validated.
+func Load(path string, env string, lookupEnv func(string) (string, bool), flags *flag.FlagSet) (Config, error) {
+	cfg := Default()
+	if err := cfg.readFile(path, env); err != nil {
+		return Config{}, err
+	}
+
+	if lookupEnv != nil {
+		for _, name := range settingNames() {
+			if value, ok := lookupEnv(EnvName(name)); ok {
+				if err := cfg.Set(name, value); err != nil {
+					return Config{}, fmt.Errorf("error in environment variable %s; %w", EnvName(name), err)
+				}
+			}
+		}
+	}
+
+	if flags != nil {
+		var err error
+		flags.Visit(func(f *flag.Flag) {
+			if err == nil && isSetting(f.Name) {
+				if setErr := cfg.Set(f.Name, f.Value.String()); setErr != nil {
+					err = fmt.Errorf("error in flag -%s; %w", f.Name, setErr)
+				}
+			}
+		})
+		if err != nil {
+			return Config{}, err
+		}
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// readFile decodes the settings of env, which are either a mapping or a list of single key mappings
+func (c *Config) readFile(path string, env string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error when reading config file %s; %w", path, err)
+	}
+
+	var envs map[string]yaml.Node
+	if err = yaml.Unmarshal(content, &envs); err != nil {
+		return fmt.Errorf("error when parsing config file %s; %w", path, err)
+	}
+	node, ok := envs[env]
+	if !ok {
+		return fmt.Errorf("config file %s has no environment '%s'", path, env)
+	}
+
+	settings := []*yaml.Node{&node}
+	if node.Kind == yaml.SequenceNode {
+		settings = node.Content
+	}
+	for _, s := range settings {
+		if err = s.Decode(c); err != nil {
+			return fmt.Errorf("error when decoding environment '%s' of config file %s; %w", env, path, err)
+		}
+	}
+	return nil
+}
+
+// DefineFlags defines a string flag on flags for every setting, flags left unset keep the value
+// of the config file
+func DefineFlags(flags *flag.FlagSet) {
+	for _, name := range settingNames() {
+		flags.String(name, "", fmt.Sprintf("overrides %s of the config file, also set by %s", name, EnvName(name)))
+	}
+}
+
+// Set parses value into the setting with the given yaml key
+func (c *Config) Set(name string, value string) error {
+	field, ok := c.setting(name)
+	if !ok {
+		return fmt.Errorf("unknown setting '%s'", name)
+	}
+
+	switch {
+	case field.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration '%s' for %s; %w", value, name, err)
+		}
+		field.SetInt(int64(d))
+	case field.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer '%s' for %s; %w", value, name, err)
+		}
+		field.SetInt(int64(n))
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+	case field.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number '%s' for %s; %w", value, name, err)
+		}
+		field.SetFloat(f)
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean '%s' for %s; %w", value, name, err)
+		}
+		field.SetBool(b)
+	case field.Type() == stringsType:
+		values := make([]string, 0)
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		field.Set(reflect.ValueOf(values))
+	default:
+		return fmt.Errorf("setting %s of type %s cannot be set", name, field.Type()) // should never happen
+	}
+	return nil
+}
+
+// Validate reports every invalid setting
+func (c Config) Validate() error {
+	errs := make([]error, 0)
+	invalid := func(name string, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("invalid %s; %s", name, fmt.Sprintf(format, args...)))
+	}
+
+	if c.Port < 1 || c.Port > 65535 {
+		invalid("port", "%d is not between 1 and 65535", c.Port)
+	}
+	if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+		invalid("logLevel", "%s", err)
+	}
+	if c.Logger != "zerolog" && c.Logger != "zap" {
+		invalid("logger", "'%s' is not zerolog or zap", c.Logger)
+	}
+	if _, err := c.Site(); err != nil {
+		invalid("wiki site", "%s", err)
+	}
+	if c.MaxConcurrent < 1 {
+		invalid("maxConcurrentRequests", "%d is less than 1", c.MaxConcurrent)
+	}
+	if c.RequestsPerSecond < 0 {
+		invalid("requestsPerSecond", "%g is negative", c.RequestsPerSecond)
+	}
+	if c.RequestBurst < 1 {
+		invalid("requestBurst", "%d is less than 1", c.RequestBurst)
+	}
+	if c.MaxRetries < 0 {
+		invalid("maxRetries", "%d is negative", c.MaxRetries)
+	}
+	if c.InitialBackoff < 0 {
+		invalid("initialBackoff", "%s is negative", c.InitialBackoff)
+	}
+	if c.MaxBackoff < c.InitialBackoff {
+		invalid("maxBackoff", "%s is less than initialBackoff %s", c.MaxBackoff, c.InitialBackoff)
+	}
+	if strings.TrimSpace(c.UserAgent) == "" {
+		invalid("userAgent", "it must not be empty")
+	}
+	switch c.LinkSource {
+	case "html", "api":
+	case "graph":
+		if c.GraphFile == "" {
+			invalid("graphFile", "it must not be empty for the graph link source")
+		}
+	default:
+		invalid("linkSource", "'%s' is not html, api or graph", c.LinkSource)
+	}
+	if c.LinkCacheDir == "" {
+		invalid("linkCacheDir", "it must not be empty")
+	}
+	if c.LinkCacheTtl < 0 {
+		invalid("linkCacheTtl", "%s is negative", c.LinkCacheTtl)
+	}
+	if c.MaxSteps < 1 {
+		invalid("maxSteps", "%d is less than 1", c.MaxSteps)
+	}
+	if c.NumWorkers < 1 {
+		invalid("numWorkers", "%d is less than 1", c.NumWorkers)
+	}
+	if c.StepTimeout <= 0 {
+		invalid("stepTimeout", "%s is not positive", c.StepTimeout)
+	}
+	if c.MaxRunningJobs < 1 {
+		invalid("maxRunningJobs", "%d is less than 1", c.MaxRunningJobs)
+	}
+	if c.ShutdownTimeout <= 0 {
+		invalid("shutdownTimeout", "%s is not positive", c.ShutdownTimeout)
+	}
+	return errors.Join(errs...)
+}
+
+// Politeness is the policy requests to the wiki follow
+func (c Config) Politeness() wikiSteps.PolitenessPolicy {
+	return wikiSteps.PolitenessPolicy{
+		MaxConcurrent:     c.MaxConcurrent,
+		RequestsPerSecond: c.RequestsPerSecond,
+		Burst:             c.RequestBurst,
+		MaxRetries:        c.MaxRetries,
+		InitialBackoff:    c.InitialBackoff,
+		MaxBackoff:        c.MaxBackoff,
+		UserAgent:         c.UserAgent,
+	}
+}
+
+// Site is the MediaWiki install described by the wiki settings
+func (c Config) Site() (wikiSteps.WikiSite, error) {
+	return wikiSteps.NewWikiSite(c.WikiHost, c.ArticlePath, c.ApiPath, c.CapitalLinks, c.BlockedTitles, c.BlockedTitlePattern)
+}
+
+// Addr is the address the HTTP server listens on
+func (c Config) Addr() string {
+	return fmt.Sprintf(":%d", c.Port)
+}
+
+// EnvName returns the environment variable overriding a setting, APP_MAX_STEPS for maxSteps
+func EnvName(name string) string {
+	var b strings.Builder
+	b.WriteString(EnvPrefix)
+	for i, r := range name {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+func settingNames() []string {
+	t := reflect.TypeOf(Config{})
+	names := make([]string, t.NumField())
+	for i := range t.NumField() {
+		names[i] = t.Field(i).Tag.Get("yaml")
+	}
+	return names
+}
+
+func isSetting(name string) bool {
+	_, ok := (&Config{}).setting(name)
+	return ok
+}
+
+func (c *Config) setting(name string) (reflect.Value, bool) {
+	v := reflect.ValueOf(c).Elem()
+	for i := range v.NumField() {
+		if v.Type().Field(i).Tag.Get("yaml") == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
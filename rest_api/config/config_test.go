@@ -0,0 +1,147 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+dev:
+  - containerName: "learning go app"
+  - logLevel: debug
+prod:
+  logLevel: info
+  logger: zap
+  maxSteps: 5
+  stepTimeout: 1m
+`)
+
+	dev := Default()
+	dev.LogLevel = "debug"
+	prod := Default()
+	prod.LogLevel, prod.Logger, prod.MaxSteps, prod.StepTimeout = "info", "zap", 5, time.Minute
+	tests := map[string]Config{
+		"dev":  dev,
+		"prod": prod,
+	}
+	for env, expected := range tests {
+		if cfg, err := Load(path, env, nil, nil); err != nil || !reflect.DeepEqual(cfg, expected) {
+			t.Errorf("Env: %s Expected: %+v Actual: %+v (%v)", env, expected, cfg, err)
+		}
+	}
+
+	if _, err := Load(path, "staging", nil, nil); err == nil {
+		t.Errorf("Expected an error for an unknown environment")
+	}
+}
+
+func TestLoadOverrides(t *testing.T) {
+	path := writeConfig(t, `
+dev:
+  port: 8000
+  numWorkers: 25
+  stepTimeout: 30s
+`)
+	env := map[string]string{
+		"APP_NUM_WORKERS":         "10",
+		"APP_STEP_TIMEOUT":        "5s",
+		"APP_PORT":                "9000",
+		"APP_BLOCKED_TITLES":      "Main_Page, Wiktionary:Main_Page",
+		"APP_CAPITAL_LINKS":       "false",
+		"APP_REQUESTS_PER_SECOND": "2.5",
+	}
+	lookupEnv := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	DefineFlags(flags)
+	if err := flags.Parse([]string{"-port", "9100", "-logLevel", "trace", "-wikiHost", "https://en.wiktionary.org"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// flags take precedence over environment variables, which take precedence over the file
+	cfg, err := Load(path, "dev", lookupEnv, flags)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if cfg.NumWorkers != 10 || cfg.StepTimeout != 5*time.Second || cfg.Port != 9100 || cfg.LogLevel != "trace" {
+		t.Errorf("Expected: 10 workers, 5s timeout, port 9100 and trace level Actual: %+v", cfg)
+	}
+	site, err := cfg.Site()
+	if expected := []string{"Main_Page", "Wiktionary:Main_Page"}; err != nil || site.Host != "https://en.wiktionary.org" || site.CapitalLinks || !slices.Equal(site.BlockedTitles, expected) {
+		t.Errorf("Expected: the site of en.wiktionary.org with case-sensitive titles blocking %v Actual: %+v (%v)", expected, site, err)
+	}
+	if politeness := cfg.Politeness(); politeness.RequestsPerSecond != 2.5 || politeness.MaxRetries != Default().MaxRetries {
+		t.Errorf("Expected: 2.5 requests per second and the default retries Actual: %+v", politeness)
+	}
+	if addr := cfg.Addr(); addr != ":9100" {
+		t.Errorf("Expected: %s Actual: %s", ":9100", addr)
+	}
+
+	env["APP_MAX_STEPS"] = "many"
+	if _, err = Load(path, "dev", lookupEnv, nil); err == nil {
+		t.Errorf("Expected an error for an invalid environment variable")
+	}
+	env["APP_MAX_STEPS"], env["APP_CAPITAL_LINKS"] = "5", "sometimes"
+	if _, err = Load(path, "dev", lookupEnv, nil); err == nil {
+		t.Errorf("Expected an error for an invalid boolean environment variable")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	tests := map[string]func(*Config){
+		"port":                  func(c *Config) { c.Port = 0 },
+		"logLevel":              func(c *Config) { c.LogLevel = "verbose" },
+		"logger":                func(c *Config) { c.Logger = "logrus" },
+		"wikiHost":              func(c *Config) { c.WikiHost = "en.wikipedia.org" },
+		"articlePath":           func(c *Config) { c.ArticlePath = "wiki" },
+		"apiPath":               func(c *Config) { c.ApiPath = "w/api.php" },
+		"blockedTitlePattern":   func(c *Config) { c.BlockedTitlePattern = "(" },
+		"maxConcurrentRequests": func(c *Config) { c.MaxConcurrent = 0 },
+		"requestsPerSecond":     func(c *Config) { c.RequestsPerSecond = -1 },
+		"requestBurst":          func(c *Config) { c.RequestBurst = 0 },
+		"maxRetries":            func(c *Config) { c.MaxRetries = -1 },
+		"initialBackoff":        func(c *Config) { c.InitialBackoff = -time.Second },
+		"maxBackoff":            func(c *Config) { c.MaxBackoff = c.InitialBackoff - time.Millisecond },
+		"userAgent":             func(c *Config) { c.UserAgent = " " },
+		"linkSource":            func(c *Config) { c.LinkSource = "dump" },
+		"graphFile":             func(c *Config) { c.LinkSource, c.GraphFile = "graph", "" },
+		"maxSteps":              func(c *Config) { c.MaxSteps = 0 },
+		"numWorkers":            func(c *Config) { c.NumWorkers = -1 },
+		"stepTimeout":           func(c *Config) { c.StepTimeout = 0 },
+		"maxRunningJobs":        func(c *Config) { c.MaxRunningJobs = 0 },
+		"shutdownTimeout":       func(c *Config) { c.ShutdownTimeout = 0 },
+	}
+	for name, invalidate := range tests {
+		cfg := Default()
+		invalidate(&cfg)
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Expected an error for an invalid %s", name)
+		}
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	if name := EnvName("linkCacheTtl"); name != "APP_LINK_CACHE_TTL" {
+		t.Errorf("Expected: %s Actual: %s", "APP_LINK_CACHE_TTL", name)
+	}
+}
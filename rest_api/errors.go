@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	wikiSteps "app/rest_api/wiki_steps"
+)
+
+// apiError is the body of every error response
+type apiError struct {
+	status  int
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Field   string `json:"field,omitempty"` // request parameter at fault
+}
+
+func (e apiError) Error() string {
+	return e.Message
+}
+
+func missingParameter(field string) apiError {
+	return apiError{http.StatusBadRequest, "missing_parameter", fmt.Sprintf("%s is required", field), field}
+}
+
+func invalidParameter(field string, format string, args ...any) apiError {
+	return apiError{http.StatusBadRequest, "invalid_parameter", fmt.Sprintf(format, args...), field}
+}
+
+func notFound(message string) apiError {
+	return apiError{http.StatusNotFound, "not_found", message, ""}
+}
+
+// searchErrorStatus maps the kinds of wikiSteps.SearchError and the errors of wikiSteps.SearchJobs to a status and code
+var searchErrorStatus = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{wikiSteps.ErrInvalidUrl, http.StatusBadRequest, "invalid_url"},
+	{wikiSteps.ErrInvalidOption, http.StatusBadRequest, "invalid_option"},
+	{wikiSteps.ErrStepsOutOfRange, http.StatusUnprocessableEntity, "steps_out_of_range"},
+	{wikiSteps.ErrUnsupportedOption, http.StatusUnprocessableEntity, "unsupported_option"},
+	{wikiSteps.ErrUpstreamFailure, http.StatusBadGateway, "upstream_failure"},
+	{wikiSteps.ErrTimeout, http.StatusGatewayTimeout, "timeout"},
+	{wikiSteps.ErrCancelled, http.StatusServiceUnavailable, "cancelled"}, // the client left or the server is shutting down
+	{wikiSteps.ErrShuttingDown, http.StatusServiceUnavailable, "shutting_down"},
+}
+
+// toApiError keeps the message of unexpected errors, which are answered with a 500
+func toApiError(err error) apiError {
+	var apiErr apiError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	var field string
+	var searchErr *wikiSteps.SearchError
+	if errors.As(err, &searchErr) {
+		field = searchErr.Field
+	}
+	for _, s := range searchErrorStatus {
+		if errors.Is(err, s.kind) {
+			return apiError{s.status, s.code, err.Error(), field}
+		}
+	}
+	return apiError{http.StatusInternalServerError, "internal_error", err.Error(), field}
+}
+
+// writeError answers the request with err, the handler must return afterwards
+func writeError(w http.ResponseWriter, err error) {
+	apiErr := toApiError(err)
+	if apiErr.status >= http.StatusInternalServerError {
+		App.log.Error("Request failed", "code", apiErr.Code, "error", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apiErr.status)
+	json.NewEncoder(w).Encode(apiErr)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"app/rest_api/config"
+	"app/rest_api/logging"
+	wikiSteps "app/rest_api/wiki_steps"
+)
+
+func initTestService() wikiSteps.WikiSite {
+	site := wikiSteps.Wikipedia("en")
+	App = Application{logging.NewZerologAdapterWithWriter(io.Discard, logging.InfoLevel, func(int) {}), "test", config.Default()}
+	source := wikiSteps.NewMemoryLinkSource(map[string][]string{
+		site.PageUrl("A"): {site.PageUrl("B"), site.PageUrl("D")},
+		site.PageUrl("B"): {site.PageUrl("C")},
+		site.PageUrl("D"): {site.PageUrl("E")},
+		site.PageUrl("E"): {site.PageUrl("C")},
+	})
+	WikiStepService = wikiSteps.NewWikistepsServiceWithSource(App.log, site, source, 5, 10*time.Second, 2)
+	return site
+}
+
+func TestInvokeWikiStepServiceErrors(t *testing.T) {
+	site := initTestService()
+	tests := []struct {
+		query  url.Values
+		status int
+		code   string
+		field  string
+	}{
+		{url.Values{"start": {site.PageUrl("A")}, "target": {site.PageUrl("C")}}, http.StatusBadRequest, "missing_parameter", "steps"},
+		{url.Values{"start": {site.PageUrl("A")}, "target": {site.PageUrl("C")}, "steps": {"two"}}, http.StatusBadRequest, "invalid_parameter", "steps"},
+		{url.Values{"start": {site.PageUrl("A")}, "target": {site.PageUrl("C")}, "steps": {"9"}}, http.StatusUnprocessableEntity, "steps_out_of_range", "steps"},
+		{url.Values{"start": {"https://example.org/wiki/A"}, "target": {site.PageUrl("C")}, "steps": {"2"}}, http.StatusBadRequest, "invalid_url", "start"},
+		{url.Values{"start": {site.PageUrl("A")}, "target": {site.PageUrl("C")}, "steps": {"2"}, "strategy": {"dfs"}}, http.StatusBadRequest, "invalid_parameter", "strategy"},
+	}
+
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		invokeWikiStepService(recorder, httptest.NewRequest("GET", "/wikisteps?"+test.query.Encode(), nil))
+
+		// the body must hold a single error and nothing else
+		var body apiError
+		decoder := json.NewDecoder(recorder.Body)
+		if err := decoder.Decode(&body); err != nil || decoder.More() {
+			t.Errorf("Expected a single JSON error Actual: %s (%v)", recorder.Body.String(), err)
+		}
+		if recorder.Code != test.status || body.Code != test.code || body.Field != test.field || body.Message == "" {
+			t.Errorf("Expected: %d %s of %s Actual: %d %+v", test.status, test.code, test.field, recorder.Code, body)
+		}
+	}
+}
+
+func TestInvokeWikiStepService(t *testing.T) {
+	site := initTestService()
+	query := url.Values{"start": {site.PageUrl("A")}, "target": {site.PageUrl("C")}, "steps": {"2"}, "strategy": {"shortest"}}
+
+	recorder := httptest.NewRecorder()
+	invokeWikiStepService(recorder, httptest.NewRequest("GET", "/wikisteps?"+query.Encode(), nil))
+
+	var body struct {
+		ValidPaths [][]string `json:"validPaths"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil || recorder.Code != http.StatusOK || len(body.ValidPaths) != 1 {
+		t.Errorf("Expected: %d with 1 path Actual: %d %v (%v)", http.StatusOK, recorder.Code, body.ValidPaths, err)
+	}
+}
+
+func TestPostWikiStepService(t *testing.T) {
+	site := initTestService()
+	tests := []struct {
+		body   string
+		status int
+		code   string
+		field  string
+		paths  int
+	}{
+		{`"steps":3,"strategy":"shortest","timeout":"5s","workers":1`, http.StatusOK, "", "", 1},
+		{`"steps":3,"strategy":"shortest","exclude":["` + site.PageUrl("B") + `"]`, http.StatusOK, "", "", 1},
+		{`"steps":3,"workers":1,"through":["` + site.PageUrl("D") + `"]`, http.StatusOK, "", "", 1},
+		{`"steps":3,"strategy":"shortest","through":["` + site.PageUrl("D") + `"]`, http.StatusUnprocessableEntity, "unsupported_option", "strategy", 0},
+		{`"strategy":"shortest"`, http.StatusBadRequest, "missing_parameter", "steps", 0},
+		{`"steps":3,"timeout":"soon"`, http.StatusBadRequest, "invalid_parameter", "timeout", 0},
+		{`"steps":3,"depth":3`, http.StatusBadRequest, "invalid_body", "", 0},
+		{`"steps":3,"exclude":["https://example.org/wiki/B"]`, http.StatusBadRequest, "invalid_url", "exclude", 0},
+	}
+
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		body := `{"start":"` + site.PageUrl("A") + `","target":"` + site.PageUrl("C") + `",` + test.body + `}`
+		postWikiStepService(recorder, httptest.NewRequest("POST", "/wikisteps", strings.NewReader(body)))
+
+		var response struct {
+			apiError
+			ValidPaths [][]string `json:"validPaths"`
+		}
+		if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+			t.Fatalf("Expected a JSON body Actual: %v", err)
+		}
+		if recorder.Code != test.status || response.Code != test.code || response.Field != test.field || len(response.ValidPaths) != test.paths {
+			t.Errorf("Expected: %d %s of %s with %d paths Actual: %d %+v", test.status, test.code, test.field, test.paths, recorder.Code, response)
+		}
+	}
+}
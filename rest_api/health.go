@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+)
+
+const upstreamProbeTimeout time.Duration = 5 * time.Second
+
+// Ready is set once the search service and jobs are initialized and cleared on shutdown
+var Ready atomic.Bool
+
+type versionResponse struct {
+	Module    string        `json:"module"`
+	Version   string        `json:"version"`
+	GoVersion string        `json:"goVersion"`
+	Revision  string        `json:"revision,omitempty"` // VCS revision the binary was built from
+	Time      string        `json:"time,omitempty"`     // commit time of the revision
+	Modified  bool          `json:"modified"`           // the working tree had uncommitted changes
+	Config    configSummary `json:"config"`
+}
+
+// configSummary leaves out the paths of the config
+type configSummary struct {
+	Env            string `json:"env"`
+	ContainerName  string `json:"containerName"`
+	Logger         string `json:"logger"`
+	LogLevel       string `json:"logLevel"`
+	WikiHost       string `json:"wikiHost"`
+	LinkSource     string `json:"linkSource"`
+	MaxSteps       int    `json:"maxSteps"`
+	NumWorkers     int    `json:"numWorkers"`
+	StepTimeout    string `json:"stepTimeout"`
+	MaxRunningJobs int    `json:"maxRunningJobs"`
+}
+
+func writeJson(w http.ResponseWriter, status int, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+//curl -X GET "http://localhost:8000/healthz"
+func getHealth(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+//curl -X GET "http://localhost:8000/readyz?upstream=true"
+func getReadiness(w http.ResponseWriter, r *http.Request) {
+	// not ready until the search service is initialized and once the server shuts down
+	checks := map[string]string{"searchService": "ok"}
+	status := http.StatusOK
+	if !Ready.Load() || WikiStepService == nil || SearchJobs == nil {
+		checks["searchService"] = "not initialized"
+		status = http.StatusServiceUnavailable
+	}
+
+	if r.URL.Query().Get("upstream") == "true" && WikiStepService != nil {
+		ctx, cancel := context.WithTimeout(r.Context(), upstreamProbeTimeout)
+		defer cancel()
+		probed, err := WikiStepService.ProbeUpstream(ctx)
+		switch {
+		case err != nil:
+			App.log.Error("Upstream probe failed", "error", err)
+			checks["upstream"] = err.Error()
+			status = http.StatusServiceUnavailable
+		case probed:
+			checks["upstream"] = "ok"
+		default:
+			checks["upstream"] = "none"
+		}
+	}
+
+	response := map[string]any{"status": "ready", "checks": checks}
+	if status != http.StatusOK {
+		response["status"] = "not ready"
+	}
+	writeJson(w, status, response)
+}
+
+//curl -X GET "http://localhost:8000/version"
+func getVersion(w http.ResponseWriter, r *http.Request) {
+	cfg := App.config
+	response := versionResponse{
+		Config: configSummary{
+			App.env,
+			cfg.ContainerName,
+			cfg.Logger,
+			App.log.Level().String(),
+			cfg.WikiHost,
+			cfg.LinkSource,
+			cfg.MaxSteps,
+			cfg.NumWorkers,
+			cfg.StepTimeout.String(),
+			cfg.MaxRunningJobs,
+		},
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		response.Module = info.Main.Path
+		response.Version = info.Main.Version
+		response.GoVersion = info.GoVersion
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				response.Revision = s.Value
+			case "vcs.time":
+				response.Time = s.Value
+			case "vcs.modified":
+				response.Modified = s.Value == "true"
+			}
+		}
+	}
+	writeJson(w, http.StatusOK, response)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	wikiSteps "app/rest_api/wiki_steps"
+)
+
+type probingLinkSource struct {
+	*wikiSteps.MemoryLinkSource
+	err error
+}
+
+func (p probingLinkSource) Probe(ctx context.Context) error {
+	return p.err
+}
+
+func TestGetReadiness(t *testing.T) {
+	site := initTestService()
+	SearchJobs = wikiSteps.NewSearchJobs(App.log, WikiStepService, 1)
+	source := wikiSteps.NewMemoryLinkSource(nil)
+	tests := []struct {
+		ready  bool
+		query  string
+		source wikiSteps.LinkSource
+		status int
+		checks map[string]string
+	}{
+		{false, "", source, http.StatusServiceUnavailable, map[string]string{"searchService": "not initialized"}},
+		{true, "", source, http.StatusOK, map[string]string{"searchService": "ok"}},
+		{true, "?upstream=true", source, http.StatusOK, map[string]string{"searchService": "ok", "upstream": "none"}},
+		{true, "?upstream=true", probingLinkSource{source, nil}, http.StatusOK, map[string]string{"searchService": "ok", "upstream": "ok"}},
+		{true, "?upstream=true", probingLinkSource{source, errors.New("unreachable")}, http.StatusServiceUnavailable, map[string]string{"searchService": "ok", "upstream": "unreachable"}},
+	}
+	defer Ready.Store(false)
+
+	for _, test := range tests {
+		Ready.Store(test.ready)
+		WikiStepService = wikiSteps.NewWikistepsServiceWithSource(App.log, site, test.source, 5, 10*time.Second, 2)
+		recorder := httptest.NewRecorder()
+		getReadiness(recorder, httptest.NewRequest("GET", "/readyz"+test.query, nil))
+
+		var body struct {
+			Checks map[string]string `json:"checks"`
+		}
+		if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil || recorder.Code != test.status || len(body.Checks) != len(test.checks) {
+			t.Errorf("Expected: %d %v Actual: %d %v (%v)", test.status, test.checks, recorder.Code, body.Checks, err)
+			continue
+		}
+		for name, check := range test.checks {
+			if body.Checks[name] != check {
+				t.Errorf("Expected: %s %s Actual: %s", name, check, body.Checks[name])
+			}
+		}
+	}
+}
+
+func TestGetVersion(t *testing.T) {
+	initTestService()
+	recorder := httptest.NewRecorder()
+	getVersion(recorder, httptest.NewRequest("GET", "/version", nil))
+
+	var body versionResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil || recorder.Code != http.StatusOK {
+		t.Fatalf("Expected: %d Actual: %d (%v)", http.StatusOK, recorder.Code, err)
+	}
+	if body.GoVersion == "" || body.Config.Env != "test" || body.Config.StepTimeout != App.config.StepTimeout.String() {
+		t.Errorf("Expected: the go version and config summary Actual: %+v", body)
+	}
+}
+
+func TestGetHealth(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	getHealth(recorder, httptest.NewRequest("GET", "/healthz", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected: %d Actual: %d", http.StatusOK, recorder.Code)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"app/rest_api/logging"
+	wikiSteps "app/rest_api/wiki_steps"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// go run ./rest_api/import_dump -page enwiki-latest-page.sql.gz -pagelinks enwiki-latest-pagelinks.sql.gz -linktarget enwiki-latest-linktarget.sql.gz -redirect enwiki-latest-redirect.sql.gz -out wiki_graph.bin
+func main() {
+	log := logging.NewZerologAdapter(logging.DebugLevel)
+
+	pagePath := flag.String("page", "", "page.sql or page.sql.gz dump")
+	pagelinksPath := flag.String("pagelinks", "", "pagelinks.sql or pagelinks.sql.gz dump")
+	linktargetPath := flag.String("linktarget", "", "linktarget.sql or linktarget.sql.gz dump, required by pagelinks dumps with pl_target_id")
+	redirectPath := flag.String("redirect", "", "redirect.sql or redirect.sql.gz dump, links to redirect pages are dropped without it")
+	outPath := flag.String("out", "wiki_graph.bin", "link graph file to write")
+	flag.Parse()
+
+	if *pagePath == "" || *pagelinksPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	pageDump, err := openDump(*pagePath)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	defer pageDump.Close()
+
+	pagelinksDump, err := openDump(*pagelinksPath)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	defer pagelinksDump.Close()
+
+	var linktargetDump io.Reader
+	if *linktargetPath != "" {
+		dump, err := openDump(*linktargetPath)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		defer dump.Close()
+		linktargetDump = dump
+	}
+
+	var redirectDump io.Reader
+	if *redirectPath != "" {
+		dump, err := openDump(*redirectPath)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		defer dump.Close()
+		redirectDump = dump
+	}
+
+	out, err := os.Create(*outPath)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	if _, err = wikiSteps.ImportPagelinksDump(log, pageDump, pagelinksDump, linktargetDump, redirectDump, out); err != nil {
+		out.Close()
+		log.Fatal(err.Error())
+	}
+	if err = out.Close(); err != nil {
+		log.Fatal(err.Error())
+	}
+}
+
+type dumpFile struct {
+	io.Reader
+	file *os.File
+}
+
+func (d dumpFile) Close() error {
+	return d.file.Close()
+}
+
+// openDump opens a dump, decompressing it when the name ends with .gz
+func openDump(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error when opening dump %s; %w", path, err)
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return file, nil
+	}
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error when decompressing dump %s; %w", path, err)
+	}
+	return dumpFile{reader, file}, nil
+}
@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+type Level int32
+
+const (
+	TraceLevel Level = iota
+	DebugLevel
+	InfoLevel
+	ErrorLevel
+	FatalLevel
+)
+
+// LevelLogger is implemented by loggers whose level can be changed while they are in use,
+// the level is shared with the child loggers returned by With
+type LevelLogger interface {
+	Logger
+	Level() Level
+	SetLevel(level Level)
+	Sync() error // flushes the entries buffered by the logger or its writer
+}
+
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "trace":
+		return TraceLevel, nil
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	case "fatal":
+		return FatalLevel, nil
+	default:
+		return InfoLevel, fmt.Errorf("unknown log level '%s'", s)
+	}
+}
+
+func (l Level) String() string {
+	switch l {
+	case TraceLevel:
+		return "trace"
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case ErrorLevel:
+		return "error"
+	case FatalLevel:
+		return "fatal"
+	default:
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+}
+
+// atomicLevel is the level of a logger and its children
+type atomicLevel struct {
+	level atomic.Int32
+}
+
+func newAtomicLevel(level Level) *atomicLevel {
+	l := &atomicLevel{}
+	l.Set(level)
+	return l
+}
+
+func (l *atomicLevel) Get() Level {
+	return Level(l.level.Load())
+}
+
+func (l *atomicLevel) Set(level Level) {
+	l.level.Store(int32(level))
+}
+
+func (l *atomicLevel) Enabled(level Level) bool {
+	return level >= l.Get()
+}
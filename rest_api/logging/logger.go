@@ -1,9 +1,12 @@
 package logging
 
+// Logger logs messages with optional fields given as alternating keys and values,
+// for example log.Debug("Worker started a job", "worker", name, "url", url)
 type Logger interface {
-	Trace(msg string)
-	Debug(msg string)
-	Info(msg string)
-	Error(msg string)
-	Fatal(msg string)
+	Trace(msg string, fields ...any)
+	Debug(msg string, fields ...any)
+	Info(msg string, fields ...any)
+	Error(msg string, fields ...any)
+	Fatal(msg string, fields ...any)
+	With(fields ...any) Logger // returns a child logger adding the fields to every message
 }
@@ -0,0 +1,174 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+type newTestLogger func(w io.Writer, level Level, exit func(code int)) LevelLogger
+
+// every Logger implementation is added here to run the conformance tests
+var loggerImplementations map[string]newTestLogger = map[string]newTestLogger{
+	"zerolog": func(w io.Writer, level Level, exit func(code int)) LevelLogger {
+		return NewZerologAdapterWithWriter(w, level, exit)
+	},
+	"zap": func(w io.Writer, level Level, exit func(code int)) LevelLogger {
+		return NewZapAdapterWithWriter(w, level, exit)
+	},
+}
+
+func noExit(t *testing.T) func(code int) {
+	return func(code int) {
+		t.Errorf("Unexpected exit with code %d", code)
+	}
+}
+
+func logLines(t *testing.T, out *bytes.Buffer) []map[string]any {
+	lines := make([]map[string]any, 0)
+	for _, l := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if l == "" {
+			continue
+		}
+		var line map[string]any
+		if err := json.Unmarshal([]byte(l), &line); err != nil {
+			t.Fatalf("Unexpected error: %s in line %s", err, l)
+		}
+		lines = append(lines, line)
+	}
+	out.Reset()
+	return lines
+}
+
+func TestLoggerConformance(t *testing.T) {
+	for name, newLogger := range loggerImplementations {
+		t.Run(name+"/levels", func(t *testing.T) { testLoggerLevels(t, newLogger) })
+		t.Run(name+"/fields", func(t *testing.T) { testLoggerFields(t, newLogger) })
+		t.Run(name+"/fatal", func(t *testing.T) { testLoggerFatal(t, newLogger) })
+		t.Run(name+"/sync", func(t *testing.T) { testLoggerSync(t, newLogger) })
+	}
+}
+
+func testLoggerLevels(t *testing.T, newLogger newTestLogger) {
+	var out bytes.Buffer
+	log := newLogger(&out, InfoLevel, noExit(t))
+	worker := log.With("worker", "Ada")
+
+	logAll := func(l Logger) {
+		l.Trace("trace message")
+		l.Debug("debug message")
+		l.Info("info message")
+		l.Error("error message")
+	}
+
+	logAll(worker)
+	lines := logLines(t, &out)
+	if len(lines) != 2 || lines[0]["level"] != "info" || lines[1]["level"] != "error" {
+		t.Errorf("Expected: info and error lines Actual: %v", lines)
+	}
+
+	// children share the level of their parent
+	log.SetLevel(TraceLevel)
+	logAll(worker)
+	lines = logLines(t, &out)
+	expected := []string{"trace", "debug", "info", "error"}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected: %d lines Actual: %v", len(expected), lines)
+	}
+	for i, level := range expected {
+		if lines[i]["level"] != level || lines[i]["message"] != level+" message" {
+			t.Errorf("Expected: %s Actual: %v", level, lines[i])
+		}
+	}
+	if level := worker.(LevelLogger).Level(); level != TraceLevel {
+		t.Errorf("Expected: %s Actual: %s", TraceLevel, level)
+	}
+
+	log.SetLevel(FatalLevel)
+	logAll(worker)
+	if lines = logLines(t, &out); len(lines) != 0 {
+		t.Errorf("Expected: no lines Actual: %v", lines)
+	}
+}
+
+func testLoggerFields(t *testing.T, newLogger newTestLogger) {
+	var out bytes.Buffer
+	log := newLogger(&out, DebugLevel, noExit(t))
+	worker := log.With("worker", "Ada").With("url", "https://en.wikipedia.org/wiki/A")
+	worker.Info("Worker completed a job", "links", 3, "error", errors.New("boom"))
+	log.Info("Application initialized!")
+
+	lines := logLines(t, &out)
+	if len(lines) != 2 {
+		t.Fatalf("Expected: 2 lines Actual: %v", lines)
+	}
+	expected := map[string]any{
+		"level":   "info",
+		"message": "Worker completed a job",
+		"worker":  "Ada",
+		"url":     "https://en.wikipedia.org/wiki/A",
+		"links":   3.0,
+		"error":   "boom",
+	}
+	for key, value := range expected {
+		if lines[0][key] != value {
+			t.Errorf("Field: %s Expected: %v Actual: %v", key, value, lines[0][key])
+		}
+	}
+	if _, ok := lines[0]["time"]; !ok {
+		t.Errorf("Expected a time field Actual: %v", lines[0])
+	}
+
+	// fields of a child are not added to its parent
+	if _, ok := lines[1]["worker"]; ok {
+		t.Errorf("Expected no worker field Actual: %v", lines[1])
+	}
+}
+
+func testLoggerFatal(t *testing.T, newLogger newTestLogger) {
+	var out bytes.Buffer
+	exitCode := -1
+	log := newLogger(&out, FatalLevel, func(code int) { exitCode = code })
+	log.With("worker", "Ada").Fatal("fatal message", "url", "https://en.wikipedia.org/wiki/A")
+
+	if exitCode != 1 {
+		t.Errorf("Expected: %d Actual: %d", 1, exitCode)
+	}
+	lines := logLines(t, &out)
+	if len(lines) != 1 || lines[0]["level"] != "fatal" || lines[0]["message"] != "fatal message" || lines[0]["worker"] != "Ada" {
+		t.Errorf("Expected: a fatal line Actual: %v", lines)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for _, level := range []Level{TraceLevel, DebugLevel, InfoLevel, ErrorLevel, FatalLevel} {
+		if parsed, err := ParseLevel(level.String()); err != nil || parsed != level {
+			t.Errorf("Expected: %s Actual: %s (%v)", level, parsed, err)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Errorf("Expected an error for an unknown level")
+	}
+}
+
+type syncBuffer struct {
+	bytes.Buffer
+	syncs int
+}
+
+func (b *syncBuffer) Sync() error {
+	b.syncs += 1
+	return nil
+}
+
+func testLoggerSync(t *testing.T, newLogger newTestLogger) {
+	var out syncBuffer
+	log := newLogger(&out, InfoLevel, noExit(t))
+	log.Info("message")
+	if err := log.Sync(); err != nil || out.syncs != 1 {
+		t.Errorf("Expected: 1 sync Actual: %d (%v)", out.syncs, err)
+	}
+}
@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const zapTraceLevel zapcore.Level = zapcore.DebugLevel - 1 // zap has no trace level
+
+// ZapAdapter writes the same JSON lines as ZerologAdapter
+type ZapAdapter struct {
+	logger *zap.SugaredLogger
+	level  *atomicLevel
+}
+
+func NewZapAdapter(level Level) *ZapAdapter {
+	return NewZapAdapterWithWriter(os.Stdout, level, os.Exit)
+}
+
+// NewZapAdapterWithWriter writes to w and calls exit after logging a fatal message
+func NewZapAdapterWithWriter(w io.Writer, level Level, exit func(code int)) *ZapAdapter {
+	encoderConfig := zapcore.EncoderConfig{
+		MessageKey:     "message",
+		LevelKey:       "level",
+		TimeKey:        "time",
+		EncodeLevel:    encodeZapLevel,
+		EncodeTime:     zapcore.RFC3339TimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+	}
+	// levels are filtered by the adapter so they can be changed at runtime
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(w), zapTraceLevel)
+	return &ZapAdapter{
+		logger: zap.New(core, zap.WithFatalHook(exitHook(exit))).Sugar(),
+		level:  newAtomicLevel(level),
+	}
+}
+
+func (z *ZapAdapter) Level() Level {
+	return z.level.Get()
+}
+
+func (z *ZapAdapter) SetLevel(level Level) {
+	z.level.Set(level)
+}
+
+func (z *ZapAdapter) Sync() error {
+	return z.logger.Sync()
+}
+
+func (z *ZapAdapter) Trace(msg string, fields ...any) {
+	if z.level.Enabled(TraceLevel) {
+		z.logger.Logw(zapTraceLevel, msg, fields...)
+	}
+}
+
+func (z *ZapAdapter) Debug(msg string, fields ...any) {
+	if z.level.Enabled(DebugLevel) {
+		z.logger.Debugw(msg, fields...)
+	}
+}
+
+func (z *ZapAdapter) Info(msg string, fields ...any) {
+	if z.level.Enabled(InfoLevel) {
+		z.logger.Infow(msg, fields...)
+	}
+}
+
+func (z *ZapAdapter) Error(msg string, fields ...any) {
+	if z.level.Enabled(ErrorLevel) {
+		z.logger.Errorw(msg, fields...)
+	}
+}
+
+func (z *ZapAdapter) Fatal(msg string, fields ...any) {
+	z.logger.Fatalw(msg, fields...)
+}
+
+func (z *ZapAdapter) With(fields ...any) Logger {
+	return &ZapAdapter{
+		logger: z.logger.With(fields...),
+		level:  z.level,
+	}
+}
+
+func encodeZapLevel(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	if level == zapTraceLevel {
+		enc.AppendString("trace")
+		return
+	}
+	zapcore.LowercaseLevelEncoder(level, enc)
+}
+
+// exitHook is called by zap after a fatal message was written
+type exitHook func(code int)
+
+func (h exitHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	h(1)
+}
@@ -1,6 +1,7 @@
 package logging
 
 import (
+	"io"
 	"os"
 
 	"github.com/rs/zerolog"
@@ -8,31 +9,78 @@ import (
 
 type ZerologAdapter struct {
 	logger zerolog.Logger
+	writer io.Writer
+	level  *atomicLevel
+	exit   func(code int)
 }
 
-func NewZerologAdapter() *ZerologAdapter {
-	zerolog.SetGlobalLevel(zerolog.DebugLevel)
+func NewZerologAdapter(level Level) *ZerologAdapter {
+	return NewZerologAdapterWithWriter(os.Stdout, level, os.Exit)
+}
+
+// NewZerologAdapterWithWriter writes to w and calls exit after logging a fatal message
+func NewZerologAdapterWithWriter(w io.Writer, level Level, exit func(code int)) *ZerologAdapter {
+	zerolog.SetGlobalLevel(zerolog.TraceLevel) // levels are filtered by the adapter so they can be changed at runtime
 	return &ZerologAdapter{
-		logger: zerolog.New(os.Stdout).With().Timestamp().Logger(),
+		logger: zerolog.New(w).With().Timestamp().Logger(),
+		writer: w,
+		level:  newAtomicLevel(level),
+		exit:   exit,
 	}
 }
 
-func (z *ZerologAdapter) Trace(msg string) {
-	z.logger.Trace().Msg(msg)
+func (z *ZerologAdapter) Level() Level {
+	return z.level.Get()
 }
 
-func (z *ZerologAdapter) Debug(msg string) {
-	z.logger.Debug().Msg(msg)
+func (z *ZerologAdapter) SetLevel(level Level) {
+	z.level.Set(level)
 }
 
-func (z *ZerologAdapter) Info(msg string) {
-	z.logger.Info().Msg(msg)
+// Sync flushes the writer when it can be synced, zerolog itself does not buffer
+func (z *ZerologAdapter) Sync() error {
+	if syncer, ok := z.writer.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+	return nil
 }
 
-func (z *ZerologAdapter) Error(msg string) {
-	z.logger.Error().Msg(msg)
+// fields are only encoded when the level is enabled
+func (z *ZerologAdapter) Trace(msg string, fields ...any) {
+	if z.level.Enabled(TraceLevel) {
+		z.logger.Trace().Fields(fields).Msg(msg)
+	}
 }
 
-func (z *ZerologAdapter) Fatal(msg string) {
-	z.logger.Fatal().Msg(msg)
+func (z *ZerologAdapter) Debug(msg string, fields ...any) {
+	if z.level.Enabled(DebugLevel) {
+		z.logger.Debug().Fields(fields).Msg(msg)
+	}
+}
+
+func (z *ZerologAdapter) Info(msg string, fields ...any) {
+	if z.level.Enabled(InfoLevel) {
+		z.logger.Info().Fields(fields).Msg(msg)
+	}
+}
+
+func (z *ZerologAdapter) Error(msg string, fields ...any) {
+	if z.level.Enabled(ErrorLevel) {
+		z.logger.Error().Fields(fields).Msg(msg)
+	}
+}
+
+// Fatal logs through WithLevel since zerolog's Fatal calls os.Exit itself
+func (z *ZerologAdapter) Fatal(msg string, fields ...any) {
+	z.logger.WithLevel(zerolog.FatalLevel).Fields(fields).Msg(msg)
+	z.exit(1)
+}
+
+func (z *ZerologAdapter) With(fields ...any) Logger {
+	return &ZerologAdapter{
+		logger: z.logger.With().Fields(fields).Logger(),
+		writer: z.writer,
+		level:  z.level,
+		exit:   z.exit,
+	}
 }
@@ -1,14 +1,23 @@
 package main
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"net"
 	"net/http"
-	"net/url"
+	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 	"time"
 
+	"app/rest_api/config"
 	"app/rest_api/logging"
+	"app/rest_api/metrics"
 	wikiSteps "app/rest_api/wiki_steps"
 
 	"github.com/gorilla/mux"
@@ -20,53 +29,401 @@ import (
 var (
 	App             Application
 	WikiStepService *wikiSteps.WikiSteps
+	LinkCache       *wikiSteps.PersistentLinkSource
+	SearchJobs      *wikiSteps.SearchJobs
 )
 
 type Application struct {
-	log logging.Logger
+	log    logging.LevelLogger
+	env    string
+	config config.Config
 }
 
-func initApplication() {
+func initApplication(cfg config.Config, env string) error {
+	level, err := logging.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		return fmt.Errorf("invalid logLevel in config; %w", err)
+	}
+	log, err := newLogger(cfg.Logger, level)
+	if err != nil {
+		return err
+	}
 	App = Application{
-		logging.NewZerologAdapter(),
+		log,
+		env,
+		cfg,
+	}
+	return nil
+}
+
+func newLogger(name string, level logging.Level) (logging.LevelLogger, error) {
+	switch name {
+	case "", "zerolog":
+		return logging.NewZerologAdapter(level), nil
+	case "zap":
+		return logging.NewZapAdapter(level), nil
+	default:
+		return nil, fmt.Errorf("unknown logger '%s'", name)
 	}
 }
 
 //curl -X GET "http://localhost:8000/wikisteps?start=https%3A%2F%2Fen.wikipedia.org%2Fwiki%2FFriedrich_Merz&target=https%3A%2F%2Fen.wikipedia.org%2Fwiki%2FMachine_translation&steps=5"
+//curl -X GET "http://localhost:8000/wikisteps?start=https%3A%2F%2Fen.wikipedia.org%2Fwiki%2FFriedrich_Merz&target=https%3A%2F%2Fen.wikipedia.org%2Fwiki%2FMachine_translation&steps=5&strategy=bidirectional"
+//curl -X GET "http://localhost:8000/wikisteps?start=https%3A%2F%2Fen.wikipedia.org%2Fwiki%2FFriedrich_Merz&target=https%3A%2F%2Fen.wikipedia.org%2Fwiki%2FMachine_translation&steps=5&strategy=shortest&maxResults=3"
+//curl -X GET "http://localhost:8000/wikisteps?start=https%3A%2F%2Fen.wikipedia.org%2Fwiki%2FFriedrich_Merz&target=https%3A%2F%2Fen.wikipedia.org%2Fwiki%2FMachine_translation&steps=5&strategy=shortest&extraction=article"
+//curl -X GET "http://localhost:8000/wikisteps?start=https%3A%2F%2Fen.wikipedia.org%2Fwiki%2FFriedrich_Merz&target=https%3A%2F%2Fen.wikipedia.org%2Fwiki%2FMachine_translation&steps=5&strategy=bestfirst&maxResults=1"
 func invokeWikiStepService(w http.ResponseWriter, r *http.Request) {
+	query, err := parseSearchQuery(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	runWikiStepSearch(w, r, query)
+}
+
+//curl -X POST "http://localhost:8000/wikisteps" -H "Content-Type: application/json" -d '{"start":"https://en.wikipedia.org/wiki/Friedrich_Merz","target":"https://en.wikipedia.org/wiki/Machine_translation","steps":5,"strategy":"shortest","maxResults":3,"timeout":"20s","workers":10,"extraction":"article","exclude":["https://en.wikipedia.org/wiki/Germany"]}'
+//curl -X POST "http://localhost:8000/wikisteps" -H "Content-Type: application/json" -d '{"start":"https://en.wikipedia.org/wiki/Friedrich_Merz","target":"https://en.wikipedia.org/wiki/Machine_translation","steps":5,"exclude":["https://en.wikipedia.org/wiki/United_States"],"through":["https://en.wikipedia.org/wiki/Philosophy"]}'
+func postWikiStepService(w http.ResponseWriter, r *http.Request) {
+	query, err := parseSearchBody(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	runWikiStepSearch(w, r, query)
+}
+
+func runWikiStepSearch(w http.ResponseWriter, r *http.Request, query searchQuery) {
+	result, err := WikiStepService.FindValidPaths(r.Context(), query.Start, query.Target, query.Steps, query.Options)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"start":       query.Start,
+		"target":      query.Target,
+		"steps":       query.Steps,
+		"strategy":    query.Options.Strategy.String(),
+		"maxResults":  query.Options.MaxResults,
+		"extraction":  query.Options.Extraction.String(),
+		"timeout":     query.Options.Timeout.String(),
+		"workers":     query.Options.NumWorkers,
+		"exclude":     query.Options.Exclude,
+		"through":     query.Options.Through,
+		"categories":  query.Options.Categories,
+		"validPaths":  result.Paths,
+		"minimal":     result.Minimal,
+		"timedOut":    result.TimedOut,
+		"cancelled":   result.Cancelled,
+		"cacheHits":   result.CacheHits,
+		"cacheMisses": result.CacheMisses,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+type searchQuery struct {
+	Start   string
+	Target  string
+	Steps   int
+	Options wikiSteps.SearchOptions
+}
+
+// parseSearchQuery returns an apiError naming the first missing or invalid parameter
+func parseSearchQuery(r *http.Request) (searchQuery, error) {
+	var query searchQuery
 	var err error
 	quaryParams := r.URL.Query()
-	start := quaryParams.Get("start")
-	target := quaryParams.Get("target")
-	steps := quaryParams.Get("steps")
 
-	if start, err = url.QueryUnescape(start); err != nil {
-		http.Error(w, "One or more required query parameters is invalid", http.StatusBadRequest)
+	for _, name := range []string{"start", "target", "steps"} {
+		if quaryParams.Get(name) == "" {
+			return query, missingParameter(name)
+		}
+	}
+	query.Start = quaryParams.Get("start")
+	query.Target = quaryParams.Get("target")
+
+	if query.Steps, err = strconv.Atoi(quaryParams.Get("steps")); err != nil {
+		return query, invalidParameter("steps", "steps must be an integer")
+	}
+
+	if query.Options.Strategy, err = wikiSteps.ParseSearchStrategy(quaryParams.Get("strategy")); err != nil {
+		return query, invalidParameter("strategy", "%s", err)
+	}
+
+	if maxResults := quaryParams.Get("maxResults"); maxResults != "" {
+		if query.Options.MaxResults, err = strconv.Atoi(maxResults); err != nil {
+			return query, invalidParameter("maxResults", "maxResults must be an integer")
+		}
+	}
+
+	if query.Options.Extraction, err = wikiSteps.ParseExtractionPolicy(quaryParams.Get("extraction")); err != nil {
+		return query, invalidParameter("extraction", "%s", err)
+	}
+
+	// path constraints are repeated parameters, such as through=...&through=...
+	query.Options.Exclude = quaryParams["exclude"]
+	query.Options.Through = quaryParams["through"]
+	query.Options.Categories = quaryParams["category"]
+	return query, nil
+}
+
+// searchBody is the JSON body of POST /wikisteps, every field but start, target and steps is optional
+type searchBody struct {
+	Start      string   `json:"start"`
+	Target     string   `json:"target"`
+	Steps      *int     `json:"steps"`
+	Strategy   string   `json:"strategy"`
+	MaxResults int      `json:"maxResults"`
+	Timeout    string   `json:"timeout"` // a duration such as 20s, capped by the server's stepTimeout
+	Workers    int      `json:"workers"` // capped by the server's numWorkers
+	Extraction string   `json:"extraction"`
+	Exclude    []string `json:"exclude"`    // pages no path may pass through
+	Through    []string `json:"through"`    // pages every path must pass through
+	Categories []string `json:"categories"` // pages between start and target must be in one of them
+}
+
+// parseSearchBody returns an apiError naming the first missing or invalid field
+func parseSearchBody(r *http.Request) (searchQuery, error) {
+	var query searchQuery
+	var body searchBody
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&body); err != nil {
+		return query, apiError{http.StatusBadRequest, "invalid_body", fmt.Sprintf("request body is not a valid search; %s", err), ""}
+	}
+
+	switch {
+	case body.Start == "":
+		return query, missingParameter("start")
+	case body.Target == "":
+		return query, missingParameter("target")
+	case body.Steps == nil:
+		return query, missingParameter("steps")
+	}
+	query.Start, query.Target, query.Steps = body.Start, body.Target, *body.Steps
+
+	var err error
+	if query.Options.Strategy, err = wikiSteps.ParseSearchStrategy(body.Strategy); err != nil {
+		return query, invalidParameter("strategy", "%s", err)
+	}
+
+	if body.Timeout != "" {
+		if query.Options.Timeout, err = time.ParseDuration(body.Timeout); err != nil {
+			return query, invalidParameter("timeout", "timeout must be a duration such as 20s")
+		}
+	}
+
+	if query.Options.Extraction, err = wikiSteps.ParseExtractionPolicy(body.Extraction); err != nil {
+		return query, invalidParameter("extraction", "%s", err)
+	}
+
+	query.Options.MaxResults = body.MaxResults
+	query.Options.NumWorkers = body.Workers
+	query.Options.Exclude = body.Exclude
+	query.Options.Through = body.Through
+	query.Options.Categories = body.Categories
+	return query, nil
+}
+
+func writeSearchJob(w http.ResponseWriter, status int, job *wikiSteps.SearchJob) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(job.Snapshot())
+}
+
+//curl -X POST "http://localhost:8000/wikisteps/jobs?start=https%3A%2F%2Fen.wikipedia.org%2Fwiki%2FFriedrich_Merz&target=https%3A%2F%2Fen.wikipedia.org%2Fwiki%2FMachine_translation&steps=5"
+func submitSearchJob(w http.ResponseWriter, r *http.Request) {
+	query, err := parseSearchQuery(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	job, err := SearchJobs.Submit(query.Start, query.Target, query.Steps, query.Options)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/wikisteps/jobs/"+job.Id)
+	writeSearchJob(w, http.StatusAccepted, job)
+}
+
+//curl -X GET "http://localhost:8000/wikisteps/jobs/{id}"
+func getSearchJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := SearchJobs.Get(mux.Vars(r)["id"])
+	if !ok {
+		writeError(w, notFound("Search job not found"))
+		return
+	}
+	writeSearchJob(w, http.StatusOK, job)
+}
+
+//curl -X DELETE "http://localhost:8000/wikisteps/jobs/{id}"
+func cancelSearchJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := SearchJobs.Cancel(mux.Vars(r)["id"])
+	if !ok {
+		writeError(w, notFound("Search job not found"))
+		return
+	}
+	writeSearchJob(w, http.StatusAccepted, job)
+}
+
+const streamProgressInterval = 2 * time.Second
+
+func writeServerSentEvent(w http.ResponseWriter, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	w.(http.Flusher).Flush()
+	return nil
+}
+
+//curl -N -X GET "http://localhost:8000/wikisteps/stream?start=https%3A%2F%2Fen.wikipedia.org%2Fwiki%2FFriedrich_Merz&target=https%3A%2F%2Fen.wikipedia.org%2Fwiki%2FMachine_translation&steps=5"
+func streamWikiStepService(w http.ResponseWriter, r *http.Request) {
+	query, err := parseSearchQuery(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err = WikiStepService.ValidateSearch(query.Start, query.Target, query.Steps, query.Options); err != nil {
+		writeError(w, err)
+		return
 	}
 
-	if target, err = url.QueryUnescape(target); err != nil {
-		http.Error(w, "One or more required query parameters is invalid", http.StatusBadRequest)
+	if _, ok := w.(http.Flusher); !ok {
+		writeError(w, fmt.Errorf("streaming is not supported"))
+		return
 	}
 
-	if start == "" || target == "" || steps == "" {
-		http.Error(w, "Missing one or more required query parameters", http.StatusBadRequest)
+	type searchOutcome struct {
+		result wikiSteps.SearchResult
+		err    error
 	}
 
-	stepsNum, err := strconv.Atoi(steps)
+	monitor := wikiSteps.NewSearchMonitor()
+	defer monitor.Cancel() // the search must not outlive the stream, which stops reading its paths
+	pathCh := monitor.StreamPaths()
+	doneCh := make(chan searchOutcome, 1)
+	go func() {
+		result, err := WikiStepService.FindValidPathsWithMonitor(r.Context(), query.Start, query.Target, query.Steps, query.Options, monitor)
+		doneCh <- searchOutcome{result, err}
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	ticker := time.NewTicker(streamProgressInterval)
+	defer ticker.Stop()
+	stopping, _ := r.Context().Value(stoppingKey{}).(chan struct{}) // nil unless run by serve
+
+	for {
+		select {
+		case path := <-pathCh:
+			err = writeServerSentEvent(w, "path", path)
+
+		case <-ticker.C:
+			progress := monitor.Progress()
+			progress.Paths = nil
+			err = writeServerSentEvent(w, "progress", progress)
+
+		case outcome := <-doneCh:
+			for len(pathCh) > 0 {
+				writeServerSentEvent(w, "path", <-pathCh)
+			}
+			if outcome.err != nil {
+				writeServerSentEvent(w, "error", toApiError(outcome.err))
+				return
+			}
+			writeServerSentEvent(w, "summary", map[string]interface{}{
+				"start":       query.Start,
+				"target":      query.Target,
+				"steps":       query.Steps,
+				"strategy":    query.Options.Strategy.String(),
+				"maxResults":  query.Options.MaxResults,
+				"extraction":  query.Options.Extraction.String(),
+				"pathsFound":  len(outcome.result.Paths),
+				"minimal":     outcome.result.Minimal,
+				"timedOut":    outcome.result.TimedOut,
+				"cancelled":   outcome.result.Cancelled,
+				"cacheHits":   outcome.result.CacheHits,
+				"cacheMisses": outcome.result.CacheMisses,
+			})
+			return
+
+		case <-stopping:
+			App.log.Debug("WikiSteps stream is ending its search, the server is shutting down")
+			monitor.Cancel() // the summary of the paths found so far follows once the search stopped
+			stopping = nil
+
+		case <-r.Context().Done():
+			App.log.Debug("WikiSteps stream client disconnected, search was cancelled")
+			return
+		}
+
+		if err != nil {
+			App.log.Error("Error occored when writing to WikiSteps stream", "error", err)
+			return
+		}
+	}
+}
+
+//curl -X DELETE "http://localhost:8000/admin/linkcache?expired=true"
+func purgeLinkCache(w http.ResponseWriter, r *http.Request) {
+	expiredOnly := r.URL.Query().Get("expired") == "true"
+
+	purged, err := LinkCache.Purge(expiredOnly)
 	if err != nil {
-		http.Error(w, "One or more required query parameters is invalid", http.StatusBadRequest)
+		writeError(w, fmt.Errorf("error when purging the link cache; %w", err))
+		return
+	}
+
+	response := map[string]interface{}{
+		"expiredOnly": expiredOnly,
+		"purged":      purged,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+//curl -X GET "http://localhost:8000/admin/loglevel"
+func getLogLevel(w http.ResponseWriter, r *http.Request) {
+	response := map[string]interface{}{
+		"level": App.log.Level().String(),
 	}
 
-	paths, err := WikiStepService.FindValidPaths(start, target, stepsNum)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+//curl -X PUT "http://localhost:8000/admin/loglevel?level=trace"
+func setLogLevel(w http.ResponseWriter, r *http.Request) {
+	level, err := logging.ParseLevel(r.URL.Query().Get("level"))
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Error occored when finding valid paths: %s", err.Error()), http.StatusInternalServerError)
+		writeError(w, invalidParameter("level", "%s", err))
+		return
 	}
 
+	previous := App.log.Level()
+	App.log.SetLevel(level)
+	App.log.Info("Log level was changed", "from", previous.String(), "to", level.String())
+
 	response := map[string]interface{}{
-		"start":      start,
-		"target":     target,
-		"steps":      stepsNum,
-		"validPaths": paths,
+		"previous": previous.String(),
+		"level":    level.String(),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -74,19 +431,131 @@ func invokeWikiStepService(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+func newLinkSource(name string, site wikiSteps.WikiSite, politeness wikiSteps.PolitenessPolicy, graphFile string) (wikiSteps.LinkSource, error) {
+	switch name {
+	case "html":
+		return wikiSteps.NewHttpLinkSource(App.log, site, politeness), nil
+	case "api":
+		return wikiSteps.NewApiLinkSource(App.log, site, politeness), nil
+	case "graph":
+		return wikiSteps.NewGraphLinkSource(site, graphFile)
+	default:
+		return nil, fmt.Errorf("unknown link source '%s'", name)
+	}
+}
+
 func main() {
-	initApplication()
-	App.log.Info("Application initialized!")
+	configPath := flag.String("config", "rest_api/config.yaml", "config file")
+	env := flag.String("env", cmp.Or(os.Getenv(config.EnvPrefix+"ENV"), "dev"), "environment of the config file, dev or prod")
+	config.DefineFlags(flag.CommandLine)
+	flag.Parse()
 
-	maxSteps := 7
-	numWorkers := 25
-	stepTimeout := 30 * time.Second
+	cfg, err := config.Load(*configPath, *env, os.LookupEnv, flag.CommandLine)
+	if err == nil {
+		err = initApplication(cfg, *env)
+	}
+	if err != nil {
+		logging.NewZerologAdapter(logging.InfoLevel).Fatal(err.Error())
+	}
+	App.log.Info("Application initialized!", "env", *env, "logger", cfg.Logger, "logLevel", App.log.Level().String())
 
-	WikiStepService = wikiSteps.NewWikistepsService(App.log, maxSteps, stepTimeout, numWorkers)
+	site, err := cfg.Site()
+	if err != nil {
+		App.log.Fatal(err.Error())
+	}
+	linkSource, err := newLinkSource(cfg.LinkSource, site, cfg.Politeness(), cfg.GraphFile)
+	if err != nil {
+		App.log.Fatal(err.Error())
+	}
+
+	LinkCache, err = wikiSteps.NewPersistentLinkSource(App.log, linkSource, cfg.LinkCacheDir, cfg.LinkCacheTtl)
+	if err != nil {
+		App.log.Fatal(err.Error())
+	}
+
+	WikiStepService = wikiSteps.NewWikistepsServiceWithSource(App.log, site, LinkCache, cfg.MaxSteps, cfg.StepTimeout, cfg.NumWorkers)
+	SearchJobs = wikiSteps.NewSearchJobs(App.log, WikiStepService, cfg.MaxRunningJobs)
 
 	router := mux.NewRouter()
 
 	router.HandleFunc("/wikisteps", invokeWikiStepService).Methods("GET")
+	router.HandleFunc("/wikisteps", postWikiStepService).Methods("POST")
+	router.HandleFunc("/wikisteps/stream", streamWikiStepService).Methods("GET")
+	router.HandleFunc("/wikisteps/jobs", submitSearchJob).Methods("POST")
+	router.HandleFunc("/wikisteps/jobs/{id}", getSearchJob).Methods("GET")
+	router.HandleFunc("/wikisteps/jobs/{id}", cancelSearchJob).Methods("DELETE")
+	router.HandleFunc("/admin/linkcache", purgeLinkCache).Methods("DELETE")
+	router.HandleFunc("/admin/loglevel", getLogLevel).Methods("GET")
+	router.HandleFunc("/admin/loglevel", setLogLevel).Methods("PUT")
+	router.Handle("/metrics", metrics.DefaultRegistry.Handler()).Methods("GET") //curl -X GET "http://localhost:8000/metrics"
+	router.HandleFunc("/healthz", getHealth).Methods("GET")
+	router.HandleFunc("/readyz", getReadiness).Methods("GET")
+	router.HandleFunc("/version", getVersion).Methods("GET")
+
+	listener, err := net.Listen("tcp", cfg.Addr())
+	if err != nil {
+		App.log.Fatal(err.Error())
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	Ready.Store(true)
+	App.log.Info("Server is listening", "addr", cfg.Addr())
+	if err = serve(ctx, &http.Server{Handler: router}, listener, cfg.ShutdownTimeout); err != nil {
+		App.log.Error("Server did not shut down cleanly", "error", err)
+	}
+	if err = LinkCache.Close(); err != nil {
+		App.log.Error("Link cache was not closed cleanly", "error", err)
+	}
+	App.log.Info("Server stopped")
+	App.log.Sync()
+}
 
-	App.log.Fatal(http.ListenAndServe(":8000", router).Error())
+const shutdownGrace = time.Second // how long the searches cancelled at the drain timeout have to answer
+
+// stoppingKey is the request context key of the channel closed once the server shuts down
+type stoppingKey struct{}
+
+// serve runs server until ctx is done, then stops accepting requests and lets the requests in
+// flight drainTimeout to finish. Streams end their search right away and send its summary, the
+// searches still running when drainTimeout expires are cancelled and answer with the paths
+// found so far. Search jobs are cancelled right away and keep the progress they made.
+func serve(ctx context.Context, server *http.Server, listener net.Listener, drainTimeout time.Duration) error {
+	requestCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+	stopping := make(chan struct{})
+	server.BaseContext = func(net.Listener) context.Context { return context.WithValue(requestCtx, stoppingKey{}, stopping) }
+	server.RegisterOnShutdown(func() { close(stopping) })
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.Serve(listener) }()
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("server stopped unexpectedly; %w", err)
+	case <-ctx.Done():
+	}
+
+	App.log.Info("Server is shutting down, draining requests in flight...", "drainTimeout", drainTimeout.String())
+	Ready.Store(false)
+	drainCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	stopCancelling := context.AfterFunc(drainCtx, func() {
+		App.log.Info("Requests did not drain in time, cancelling searches in flight...")
+		cancelRequests()
+	})
+	defer stopCancelling()
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), drainTimeout+shutdownGrace)
+	defer cancelShutdown()
+
+	jobsErr := make(chan error, 1)
+	go func() { jobsErr <- SearchJobs.Shutdown(shutdownCtx) }()
+	errs := make([]error, 0)
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, fmt.Errorf("requests did not drain; %w", err))
+		server.Close()
+	}
+	if err := <-jobsErr; err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	wikiSteps "app/rest_api/wiki_steps"
+)
+
+// blockingLinkSource blocks on the links of page until the search is cancelled
+type blockingLinkSource struct {
+	wikiSteps.LinkSource
+	page    string
+	blocked chan struct{}
+}
+
+func (b blockingLinkSource) Links(ctx context.Context, workerName string, url string) ([]string, error) {
+	if url == b.page {
+		close(b.blocked)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return b.LinkSource.Links(ctx, workerName, url)
+}
+
+// startTestServer serves handler with a search service blocked on page B, the path
+// through C is found once blocked is closed. Cancelling the returned context shuts it down.
+func startTestServer(t *testing.T, site wikiSteps.WikiSite, handler http.HandlerFunc, drainTimeout time.Duration) (string, blockingLinkSource, context.CancelFunc, chan error) {
+	source := blockingLinkSource{
+		wikiSteps.NewMemoryLinkSource(map[string][]string{site.PageUrl("A"): {site.PageUrl("C"), site.PageUrl("B")}}),
+		site.PageUrl("B"),
+		make(chan struct{}),
+	}
+	WikiStepService = wikiSteps.NewWikistepsServiceWithSource(App.log, site, source, 5, time.Minute, 2)
+	SearchJobs = wikiSteps.NewSearchJobs(App.log, WikiStepService, 1)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, shutdown := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- serve(ctx, &http.Server{Handler: handler}, listener, drainTimeout)
+	}()
+	return "http://" + listener.Addr().String(), source, shutdown, served
+}
+
+func TestServeShutdown(t *testing.T) {
+	site := initTestService()
+	addr, source, shutdown, served := startTestServer(t, site, invokeWikiStepService, 100*time.Millisecond)
+
+	type searchResponse struct {
+		ValidPaths [][]string `json:"validPaths"`
+		Cancelled  bool       `json:"cancelled"`
+	}
+	responses := make(chan searchResponse, 1)
+	go func() {
+		query := url.Values{"start": {site.PageUrl("A")}, "target": {site.PageUrl("C")}, "steps": {"3"}}
+		var body searchResponse
+		resp, err := http.Get(addr + "/wikisteps?" + query.Encode())
+		if err == nil {
+			err = json.NewDecoder(resp.Body).Decode(&body)
+			resp.Body.Close()
+		}
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		responses <- body
+	}()
+
+	// the path through C was found once the search is blocked on page B
+	<-source.blocked
+	shutdown()
+	if err := <-served; err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	// the search is cancelled at the drain timeout and still answers the path found before
+	if body := <-responses; !body.Cancelled || len(body.ValidPaths) != 1 {
+		t.Errorf("Expected: a cancelled search with 1 path Actual: %+v", body)
+	}
+}
+
+func TestServeShutdownStream(t *testing.T) {
+	site := initTestService()
+	drainTimeout := 5 * time.Second
+	addr, source, shutdown, served := startTestServer(t, site, streamWikiStepService, drainTimeout)
+
+	query := url.Values{"start": {site.PageUrl("A")}, "target": {site.PageUrl("C")}, "steps": {"3"}}
+	resp, err := http.Get(addr + "/wikisteps/stream?" + query.Encode())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	<-source.blocked
+	began := time.Now()
+	shutdown()
+
+	var summary struct {
+		PathsFound int  `json:"pathsFound"`
+		Cancelled  bool `json:"cancelled"`
+	}
+	event := ""
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, "event: "); ok {
+			event = name
+		} else if data, ok := strings.CutPrefix(line, "data: "); ok && event == "summary" {
+			if err = json.Unmarshal([]byte(data), &summary); err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			break
+		}
+	}
+	if event != "summary" || !summary.Cancelled || summary.PathsFound != 1 {
+		t.Errorf("Expected: the summary of a cancelled search with 1 path Actual: %s %+v", event, summary)
+	}
+	if err = <-served; err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	// the stream ended its search instead of waiting for the drain timeout
+	if elapsed := time.Since(began); elapsed >= drainTimeout {
+		t.Errorf("Expected: shut down within %s Actual: %s", drainTimeout, elapsed)
+	}
+}
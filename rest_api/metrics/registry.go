@@ -0,0 +1,294 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const textContentType string = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the upper bounds in seconds of latency histograms
+var DefaultBuckets []float64 = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// DefaultRegistry is the registry the application's metrics are registered with
+var DefaultRegistry *Registry = NewRegistry()
+
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+// Registry holds metrics and writes them in the Prometheus text exposition format
+type Registry struct {
+	mu      sync.Mutex
+	names   map[string]struct{}
+	metrics []metric
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		names: make(map[string]struct{}),
+	}
+}
+
+// register panics on duplicate names, metrics are registered once at startup
+func (r *Registry) register(name string, m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.names[name]; exists {
+		panic(fmt.Sprintf("metric %s is already registered", name))
+	}
+	r.names[name] = struct{}{}
+	r.metrics = append(r.metrics, m)
+}
+
+func (r *Registry) NewCounter(name string, help string) *Counter {
+	c := &Counter{header: header{name, help, "counter"}}
+	r.register(name, c)
+	return c
+}
+
+func (r *Registry) NewCounterVec(name string, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{
+		header:     header{name, help, "counter"},
+		labelNames: labelNames,
+		counters:   make(map[string]*Counter),
+	}
+	r.register(name, c)
+	return c
+}
+
+func (r *Registry) NewGauge(name string, help string) *Gauge {
+	g := &Gauge{header: header{name, help, "gauge"}}
+	r.register(name, g)
+	return g
+}
+
+// NewHistogram counts observations into buckets with the given upper bounds
+func (r *Registry) NewHistogram(name string, help string, buckets []float64) *Histogram {
+	bounds := slices.Sorted(slices.Values(buckets))
+	h := &Histogram{
+		header: header{name, help, "histogram"},
+		bounds: bounds,
+		counts: make([]atomic.Uint64, len(bounds)),
+	}
+	r.register(name, h)
+	return h
+}
+
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	metrics := slices.Clone(r.metrics)
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler serves the metrics for scraping
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", textContentType)
+		r.WriteText(w)
+	})
+}
+
+type header struct {
+	name string
+	help string
+	kind string
+}
+
+func (h header) write(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", h.name, strings.ReplaceAll(h.help, "\n", " "), h.name, h.kind)
+}
+
+// atomicFloat is a float64 that can be added to from multiple goroutines
+type atomicFloat struct {
+	bits atomic.Uint64
+}
+
+func (f *atomicFloat) Add(v float64) {
+	for {
+		old := f.bits.Load()
+		if f.bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+	}
+}
+
+func (f *atomicFloat) Set(v float64) {
+	f.bits.Store(math.Float64bits(v))
+}
+
+func (f *atomicFloat) Load() float64 {
+	return math.Float64frombits(f.bits.Load())
+}
+
+type Counter struct {
+	header
+	value atomicFloat
+}
+
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+// Add panics for negative values, counters only go up
+func (c *Counter) Add(v float64) {
+	if v < 0 {
+		panic(fmt.Sprintf("counter %s cannot decrease", c.name))
+	}
+	c.value.Add(v)
+}
+
+func (c *Counter) Value() float64 {
+	return c.value.Load()
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.header.write(w)
+	fmt.Fprintf(w, "%s %s\n", c.name, formatFloat(c.Value()))
+}
+
+// CounterVec is a family of counters partitioned by label values
+type CounterVec struct {
+	header
+	labelNames []string
+	mu         sync.Mutex
+	counters   map[string]*Counter // label values joined by '\xff' -> counter
+}
+
+// WithLabelValues returns the counter of the label values, given in the order of the label names
+func (c *CounterVec) WithLabelValues(values ...string) *Counter {
+	if len(values) != len(c.labelNames) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", c.name, len(c.labelNames), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counter, exists := c.counters[key]
+	if !exists {
+		counter = &Counter{header: c.header}
+		c.counters[key] = counter
+	}
+	return counter
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.mu.Lock()
+	keys := make([]string, 0, len(c.counters))
+	for k := range c.counters {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	values := make([]float64, len(keys))
+	for i, k := range keys {
+		values[i] = c.counters[k].Value()
+	}
+	c.mu.Unlock()
+
+	c.header.write(w)
+	for i, k := range keys {
+		fmt.Fprintf(w, "%s{%s} %s\n", c.name, formatLabels(c.labelNames, strings.Split(k, "\xff")), formatFloat(values[i]))
+	}
+}
+
+type Gauge struct {
+	header
+	value atomicFloat
+}
+
+func (g *Gauge) Set(v float64) {
+	g.value.Set(v)
+}
+
+func (g *Gauge) Add(v float64) {
+	g.value.Add(v)
+}
+
+func (g *Gauge) Inc() {
+	g.value.Add(1)
+}
+
+func (g *Gauge) Dec() {
+	g.value.Add(-1)
+}
+
+func (g *Gauge) Value() float64 {
+	return g.value.Load()
+}
+
+func (g *Gauge) write(w *bufio.Writer) {
+	g.header.write(w)
+	fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(g.Value()))
+}
+
+type Histogram struct {
+	header
+	bounds []float64
+	counts []atomic.Uint64 // observations per bucket, not cumulative
+	sum    atomicFloat
+	count  atomic.Uint64
+}
+
+func (h *Histogram) Observe(v float64) {
+	if i, _ := slices.BinarySearch(h.bounds, v); i < len(h.bounds) {
+		h.counts[i].Add(1)
+	}
+	h.sum.Add(v)
+	h.count.Add(1)
+}
+
+func (h *Histogram) ObserveDuration(d time.Duration) {
+	h.Observe(d.Seconds())
+}
+
+func (h *Histogram) Count() uint64 {
+	return h.count.Load()
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.header.write(w)
+	cumulative := uint64(0)
+	for i, bound := range h.bounds {
+		cumulative += h.counts[i].Load()
+		fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", h.name, formatFloat(bound), cumulative)
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", h.name, h.count.Load())
+	fmt.Fprintf(w, "%s_sum %s\n", h.name, formatFloat(h.sum.Load()))
+	fmt.Fprintf(w, "%s_count %d\n", h.name, h.count.Load())
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+var labelValueEscaper *strings.Replacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(names []string, values []string) string {
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf("%s=\"%s\"", name, labelValueEscaper.Replace(values[i]))
+	}
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistryWriteText(t *testing.T) {
+	registry := NewRegistry()
+	fetched := registry.NewCounter("pages_fetched_total", "Pages fetched.")
+	responses := registry.NewCounterVec("http_responses_total", "HTTP responses by status.", "status")
+	idle := registry.NewGauge("idle_workers", "Idle workers.")
+	latency := registry.NewHistogram("fetch_duration_seconds", "Fetch latency.", []float64{1, 0.1})
+
+	fetched.Add(2)
+	fetched.Inc()
+	responses.WithLabelValues("429").Inc()
+	responses.WithLabelValues("200").Add(5)
+	idle.Set(4)
+	idle.Dec()
+	latency.ObserveDuration(50 * time.Millisecond)
+	latency.Observe(0.5)
+	latency.Observe(3)
+
+	expected := `# HELP pages_fetched_total Pages fetched.
+# TYPE pages_fetched_total counter
+pages_fetched_total 3
+# HELP http_responses_total HTTP responses by status.
+# TYPE http_responses_total counter
+http_responses_total{status="200"} 5
+http_responses_total{status="429"} 1
+# HELP idle_workers Idle workers.
+# TYPE idle_workers gauge
+idle_workers 3
+# HELP fetch_duration_seconds Fetch latency.
+# TYPE fetch_duration_seconds histogram
+fetch_duration_seconds_bucket{le="0.1"} 1
+fetch_duration_seconds_bucket{le="1"} 2
+fetch_duration_seconds_bucket{le="+Inf"} 3
+fetch_duration_seconds_sum 3.55
+fetch_duration_seconds_count 3
+`
+	var out strings.Builder
+	if err := registry.WriteText(&out); err != nil || out.String() != expected {
+		t.Errorf("Expected: %s Actual: %s (%v)", expected, out.String(), err)
+	}
+
+	recorder := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	if contentType := recorder.Header().Get("Content-Type"); contentType != textContentType || recorder.Body.String() != expected {
+		t.Errorf("Expected: %s Actual: %s", textContentType, contentType)
+	}
+}
+
+func TestRegistryDuplicateName(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounter("searches_total", "Searches.")
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a duplicate metric name")
+		}
+	}()
+	registry.NewGauge("searches_total", "Searches.")
+}
@@ -0,0 +1,31 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketWaitCancelled(t *testing.T) {
+	bucket := NewTokenBucket(0.001, 1) // refilling a token takes about 17 minutes
+	if err := bucket.Wait(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	bucket.mu.Lock()
+	before := bucket.tokens
+	bucket.mu.Unlock()
+	if err := bucket.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected: %v Actual: %v", context.DeadlineExceeded, err)
+	}
+
+	// only the refill of the cancelled wait is left, far less than a token
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+	if diff := bucket.tokens - before; diff < 0 || diff > 0.01 {
+		t.Errorf("Expected: %v tokens Actual: %v", before, bucket.tokens)
+	}
+}
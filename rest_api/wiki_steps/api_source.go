@@ -0,0 +1,255 @@
+package wikiSteps
+
+import (
+	"app/rest_api/logging"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const apiMaxTitles int = 50 // titles the API accepts in a single query
+
+type apiQueryResponse struct {
+	Continue map[string]string `json:"continue"`
+	Query    struct {
+		Pages []struct {
+			Title     string         `json:"title"`
+			Missing   bool           `json:"missing"`
+			Links     []apiPageTitle `json:"links"`
+			Redirects []apiPageTitle `json:"redirects"`
+		} `json:"pages"`
+		Normalized      []apiTitleChange `json:"normalized"`
+		Redirects       []apiTitleChange `json:"redirects"`
+		Backlinks       []apiPageTitle   `json:"backlinks"`
+		CategoryMembers []apiPageTitle   `json:"categorymembers"`
+	} `json:"query"`
+	Error *struct {
+		Code string `json:"code"`
+		Info string `json:"info"`
+	} `json:"error"`
+}
+
+// apiTitleChange is a title the API normalized or a redirect it followed
+type apiTitleChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type apiPageTitle struct {
+	Ns    int    `json:"ns"`
+	Title string `json:"title"`
+}
+
+// ApiLinkSource lists links through the MediaWiki Action API instead of downloading article HTML
+type ApiLinkSource struct {
+	log    logging.Logger
+	site   WikiSite
+	client *wikiClient
+}
+
+func NewApiLinkSource(log logging.Logger, site WikiSite, policy PolitenessPolicy) *ApiLinkSource {
+	return &ApiLinkSource{
+		log,
+		site,
+		newWikiClient(log, policy),
+	}
+}
+
+// Probe requests the general information of the site from the API
+func (a *ApiLinkSource) Probe(ctx context.Context) error {
+	return a.client.probe(ctx, a.site.ApiUrl()+"?action=query&meta=siteinfo&format=json")
+}
+
+func (a *ApiLinkSource) Links(ctx context.Context, workerName string, pageUrl string) ([]string, error) {
+	params := url.Values{
+		"action":      {"query"},
+		"prop":        {"links"},
+		"plnamespace": {"0"},
+		"pllimit":     {"max"},
+		"redirects":   {"1"},
+		"titles":      {a.site.PageTitle(pageUrl)},
+	}
+	fetchStart := time.Now()
+	links, err := a.queryTitles(ctx, workerName, params, func(resp apiQueryResponse) []apiPageTitle {
+		titles := make([]apiPageTitle, 0)
+		for _, p := range resp.Query.Pages {
+			titles = append(titles, p.Links...)
+		}
+		return titles
+	})
+	if err != nil {
+		return nil, err
+	}
+	observeFetch(fetchStart)
+	return links, nil
+}
+
+func (a *ApiLinkSource) Backlinks(ctx context.Context, workerName string, pageUrl string) ([]string, error) {
+	params := url.Values{
+		"action":      {"query"},
+		"list":        {"backlinks"},
+		"blnamespace": {"0"},
+		"bllimit":     {"max"},
+		"bltitle":     {a.site.PageTitle(pageUrl)},
+	}
+	fetchStart := time.Now()
+	backlinks, err := a.queryTitles(ctx, workerName, params, func(resp apiQueryResponse) []apiPageTitle {
+		return resp.Query.Backlinks
+	})
+	if err != nil {
+		return nil, err
+	}
+	observeFetch(fetchStart)
+	return backlinks, nil
+}
+
+// CategoryMembers lists the articles of the category, subcategories are not searched
+func (a *ApiLinkSource) CategoryMembers(ctx context.Context, workerName string, category string) ([]string, error) {
+	params := url.Values{
+		"action":      {"query"},
+		"list":        {"categorymembers"},
+		"cmnamespace": {"0"},
+		"cmlimit":     {"max"},
+		"cmtitle":     {categoryPrefix + category},
+	}
+	return a.queryTitles(ctx, workerName, params, func(resp apiQueryResponse) []apiPageTitle {
+		return resp.Query.CategoryMembers
+	})
+}
+
+// Resolve lets the API follow the redirect, the page returned is the page redirected to
+func (a *ApiLinkSource) Resolve(ctx context.Context, workerName string, pageUrl string) (string, error) {
+	params := url.Values{
+		"action":        {"query"},
+		"redirects":     {"1"},
+		"titles":        {a.site.PageTitle(pageUrl)},
+		"format":        {"json"},
+		"formatversion": {"2"},
+	}
+	resp, err := a.query(ctx, workerName, params)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Query.Pages) == 0 {
+		return pageUrl, nil
+	}
+	return a.site.PageUrl(resp.Query.Pages[0].Title), nil
+}
+
+// ResolveAll follows the redirects of up to apiMaxTitles pages with each query
+func (a *ApiLinkSource) ResolveAll(ctx context.Context, workerName string, pageUrls []string) (map[string]string, error) {
+	resolved := make(map[string]string)
+	for batch := range slices.Chunk(pageUrls, apiMaxTitles) {
+		titles := make([]string, len(batch))
+		for i, u := range batch {
+			titles[i] = a.site.PageTitle(u)
+		}
+		params := url.Values{
+			"action":        {"query"},
+			"redirects":     {"1"},
+			"titles":        {strings.Join(titles, "|")},
+			"format":        {"json"},
+			"formatversion": {"2"},
+		}
+		resp, err := a.query(ctx, workerName, params)
+		if err != nil {
+			return nil, err
+		}
+
+		// the API normalizes the titles before it follows their redirects
+		normalized := make(map[string]string, len(resp.Query.Normalized))
+		for _, n := range resp.Query.Normalized {
+			normalized[n.From] = n.To
+		}
+		redirects := make(map[string]string, len(resp.Query.Redirects))
+		for _, r := range resp.Query.Redirects {
+			redirects[r.From] = r.To
+		}
+		for i, title := range titles {
+			if n, ok := normalized[title]; ok {
+				title = n
+			}
+			if target, ok := redirects[title]; ok {
+				resolved[batch[i]] = a.site.PageUrl(target)
+			}
+		}
+	}
+	return resolved, nil
+}
+
+func (a *ApiLinkSource) Redirects(ctx context.Context, workerName string, pageUrl string) ([]string, error) {
+	params := url.Values{
+		"action":      {"query"},
+		"prop":        {"redirects"},
+		"rdnamespace": {"0"},
+		"rdlimit":     {"max"},
+		"titles":      {a.site.PageTitle(pageUrl)},
+	}
+	return a.queryTitles(ctx, workerName, params, func(resp apiQueryResponse) []apiPageTitle {
+		titles := make([]apiPageTitle, 0)
+		for _, p := range resp.Query.Pages {
+			titles = append(titles, p.Redirects...)
+		}
+		return titles
+	})
+}
+
+// queryTitles follows the continuation tokens of a query until every batch was read
+func (a *ApiLinkSource) queryTitles(ctx context.Context, workerName string, params url.Values, titles func(apiQueryResponse) []apiPageTitle) ([]string, error) {
+	params.Set("format", "json")
+	params.Set("formatversion", "2")
+
+	urlSet := make(map[string]struct{})
+	for {
+		resp, err := a.query(ctx, workerName, params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range titles(resp) {
+			if t.Ns != 0 {
+				continue
+			}
+			if u := a.site.PageUrl(t.Title); a.site.isValidWikiStepUrl(u) {
+				urlSet[u] = struct{}{}
+			}
+		}
+
+		if len(resp.Continue) == 0 {
+			break
+		}
+		a.log.Trace("Worker is continuing the API query", "worker", workerName, "continue", resp.Continue)
+		for k, v := range resp.Continue {
+			params.Set(k, v)
+		}
+	}
+
+	urls := make([]string, 0, len(urlSet))
+	for u := range urlSet {
+		urls = append(urls, u)
+	}
+	return urls, nil
+}
+
+func (a *ApiLinkSource) query(ctx context.Context, workerName string, params url.Values) (apiQueryResponse, error) {
+	var resp apiQueryResponse
+	requestUrl := a.site.ApiUrl() + "?" + params.Encode()
+
+	body, err := a.client.callWikipedia(ctx, workerName, requestUrl)
+	if err != nil {
+		return resp, fmt.Errorf("worker %s encountered an error when calling the MediaWiki API; %w", workerName, err)
+	}
+	defer body.Close()
+
+	if err = json.NewDecoder(body).Decode(&resp); err != nil {
+		return resp, fmt.Errorf("worker %s encountered an error when decoding the MediaWiki API response for URL %s; %w", workerName, requestUrl, err)
+	}
+	if resp.Error != nil {
+		return resp, fmt.Errorf("MediaWiki API returned error %s for URL %s: %s", resp.Error.Code, requestUrl, resp.Error.Info)
+	}
+	return resp, nil
+}
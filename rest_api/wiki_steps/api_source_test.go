@@ -0,0 +1,157 @@
+package wikiSteps
+
+import (
+	"context"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func fakeApiServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("action") != "query" || q.Get("format") != "json" {
+			t.Errorf("Unexpected API request: %s", r.URL.RawQuery)
+		}
+
+		switch {
+		case q.Get("prop") == "links" && q.Get("titles") == "Machine translation" && q.Get("plcontinue") == "":
+			io.WriteString(w, `{"continue":{"plcontinue":"123|0|Noam","continue":"||"},"query":{"pages":[{"ns":0,"title":"Machine translation","links":[{"ns":0,"title":"Computational linguistics"},{"ns":0,"title":"C++"}]}]}}`)
+		case q.Get("prop") == "links" && q.Get("plcontinue") == "123|0|Noam":
+			io.WriteString(w, `{"query":{"pages":[{"ns":0,"title":"Machine translation","links":[{"ns":0,"title":"Noam Chomsky"},{"ns":4,"title":"Wikipedia:About"}]}]}}`)
+		case q.Get("list") == "backlinks" && q.Get("bltitle") == "Machine translation" && q.Get("blcontinue") == "":
+			io.WriteString(w, `{"continue":{"blcontinue":"0|2","continue":"-||"},"query":{"backlinks":[{"pageid":1,"ns":0,"title":"Google Translate"}]}}`)
+		case q.Get("list") == "backlinks" && q.Get("blcontinue") == "0|2":
+			io.WriteString(w, `{"query":{"backlinks":[{"pageid":2,"ns":0,"title":"DeepL Translator"}]}}`)
+		case q.Get("prop") == "" && q.Get("list") == "" && q.Get("redirects") == "1" && q.Get("titles") == "Machine Translation":
+			io.WriteString(w, `{"query":{"redirects":[{"from":"Machine Translation","to":"Machine translation"}],"pages":[{"ns":0,"title":"Machine translation"}]}}`)
+		case q.Get("prop") == "" && q.Get("list") == "" && q.Get("redirects") == "1" && q.Get("titles") == "Machine Translation|mT|Noam Chomsky":
+			io.WriteString(w, `{"query":{"normalized":[{"from":"mT","to":"MT"}],"redirects":[{"from":"Machine Translation","to":"Machine translation"},{"from":"MT","to":"Machine translation"}],"pages":[{"ns":0,"title":"Machine translation"},{"ns":0,"title":"Noam Chomsky"}]}}`)
+		case q.Get("prop") == "redirects" && q.Get("titles") == "Machine translation":
+			io.WriteString(w, `{"query":{"pages":[{"ns":0,"title":"Machine translation","redirects":[{"ns":0,"title":"Machine Translation"},{"ns":0,"title":"MT"}]}]}}`)
+		case q.Get("list") == "categorymembers" && q.Get("cmtitle") == "Category:Machine_translation":
+			io.WriteString(w, `{"query":{"categorymembers":[{"pageid":2,"ns":0,"title":"Google Translate"},{"pageid":3,"ns":14,"title":"Category:Machine translation software"}]}}`)
+		default:
+			io.WriteString(w, `{"error":{"code":"badrequest","info":"unexpected request"}}`)
+		}
+	}))
+}
+
+func TestApiLinkSource(t *testing.T) {
+	server := fakeApiServer(t)
+	defer server.Close()
+	site, err := NewWikiSite(server.URL, "/wiki/", WikipediaApiPath, true, nil, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	source := NewApiLinkSource(nopLogger{}, site, DefaultPolitenessPolicy())
+	page := func(title string) string {
+		return site.Host + site.ArticlePath + title
+	}
+
+	links, err := source.Links(context.Background(), "test", page("Machine_translation"))
+	slices.Sort(links)
+	expected := []string{page("C%2B%2B"), page("Computational_linguistics"), page("Noam_Chomsky")}
+	if err != nil || !slices.Equal(links, expected) {
+		t.Errorf("Expected: %v Actual: %v (%v)", expected, links, err)
+	}
+
+	backlinks, err := source.Backlinks(context.Background(), "test", page("Machine_translation"))
+	slices.Sort(backlinks)
+	if expected := []string{page("DeepL_Translator"), page("Google_Translate")}; err != nil || !slices.Equal(backlinks, expected) {
+		t.Errorf("Expected: %v Actual: %v (%v)", expected, backlinks, err)
+	}
+
+	// the html source lists every backlink through the API as well
+	backlinks, err = NewHttpLinkSource(nopLogger{}, site, DefaultPolitenessPolicy()).Backlinks(context.Background(), "test", page("Machine_translation"))
+	slices.Sort(backlinks)
+	if expected := []string{page("DeepL_Translator"), page("Google_Translate")}; err != nil || !slices.Equal(backlinks, expected) {
+		t.Errorf("Expected: %v Actual: %v (%v)", expected, backlinks, err)
+	}
+
+	resolved, err := source.Resolve(context.Background(), "test", page("Machine_Translation"))
+	if expected := page("Machine_translation"); err != nil || resolved != expected {
+		t.Errorf("Expected: %v Actual: %v (%v)", expected, resolved, err)
+	}
+
+	resolvedAll, err := source.ResolveAll(context.Background(), "test", []string{page("Machine_Translation"), page("mT"), page("Noam_Chomsky")})
+	expectedAll := map[string]string{page("Machine_Translation"): page("Machine_translation"), page("mT"): page("Machine_translation")}
+	if err != nil || !maps.Equal(resolvedAll, expectedAll) {
+		t.Errorf("Expected: %v Actual: %v (%v)", expectedAll, resolvedAll, err)
+	}
+
+	redirects, err := source.Redirects(context.Background(), "test", page("Machine_translation"))
+	slices.Sort(redirects)
+	if expected := []string{page("MT"), page("Machine_Translation")}; err != nil || !slices.Equal(redirects, expected) {
+		t.Errorf("Expected: %v Actual: %v (%v)", expected, redirects, err)
+	}
+
+	members, err := source.CategoryMembers(context.Background(), "test", categoryName("Category:Machine translation"))
+	if expected := []string{page("Google_Translate")}; err != nil || !slices.Equal(members, expected) {
+		t.Errorf("Expected: %v Actual: %v (%v)", expected, members, err)
+	}
+
+	if _, err = source.Links(context.Background(), "test", page("Unknown")); err == nil {
+		t.Errorf("Expected an error for an API error response")
+	}
+}
+
+func TestApiLinkSourceFetchMetrics(t *testing.T) {
+	server := fakeApiServer(t)
+	defer server.Close()
+	site, err := NewWikiSite(server.URL, "/wiki/", WikipediaApiPath, true, nil, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	cache, err := NewPersistentLinkSource(nopLogger{}, NewApiLinkSource(nopLogger{}, site, DefaultPolitenessPolicy()), t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer cache.Close()
+
+	// only the first lookup is fetched from the wiki, the second one is served by the persistent cache
+	fetched, observed := pagesFetched.Value(), fetchDuration.Count()
+	for range 2 {
+		if _, err = cache.Links(context.Background(), "test", site.PageUrl("Machine_translation")); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+	if _, err = cache.Links(context.Background(), "test", site.PageUrl("Unknown")); err == nil {
+		t.Errorf("Expected an error for an API error response")
+	}
+	if fetched, observed = pagesFetched.Value()-fetched, fetchDuration.Count()-observed; fetched != 1 || observed != 1 {
+		t.Errorf("Expected: 1 page fetched and observed Actual: %v fetched %d observed", fetched, observed)
+	}
+}
+
+func TestProbeUpstream(t *testing.T) {
+	server := fakeApiServer(t)
+	site, err := NewWikiSite(server.URL, "/wiki/", WikipediaApiPath, true, nil, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	cache, err := NewPersistentLinkSource(nopLogger{}, NewApiLinkSource(nopLogger{}, site, DefaultPolitenessPolicy()), t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer cache.Close()
+	service := NewWikistepsServiceWithSource(nopLogger{}, site, cache, 5, 10*time.Second, 1)
+
+	// the api source is found behind the persistent cache
+	if probed, err := service.ProbeUpstream(context.Background()); !probed || err != nil {
+		t.Errorf("Expected: a successful probe Actual: probed %t (%v)", probed, err)
+	}
+	server.Close()
+	if probed, err := service.ProbeUpstream(context.Background()); !probed || err == nil {
+		t.Errorf("Expected: a failed probe Actual: probed %t (%v)", probed, err)
+	}
+
+	service = NewWikistepsServiceWithSource(nopLogger{}, testSite, testLinkSource(), 5, 10*time.Second, 1)
+	if probed, err := service.ProbeUpstream(context.Background()); probed || err != nil {
+		t.Errorf("Expected: nothing to probe Actual: probed %t (%v)", probed, err)
+	}
+}
@@ -0,0 +1,193 @@
+package wikiSteps
+
+import (
+	"app/rest_api/util"
+	"container/heap"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	titleOverlapWeight float64 = 2   // weight of the tokens a link's title shares with the target's title
+	neighborWeight     float64 = 1.5 // bonus of the pages the target links to
+	depthWeight        float64 = 0.25
+)
+
+var titleStopwords []string = []string{"a", "an", "and", "by", "for", "in", "list", "of", "on", "the", "to"}
+
+type bestFirstEntry struct {
+	Path  []string
+	Score float64
+	Order int // pages scored equally are expanded in the order they were found
+}
+
+// bestFirstQueue is a max-heap of entries by score
+type bestFirstQueue []bestFirstEntry
+
+func (q bestFirstQueue) Len() int { return len(q) }
+func (q bestFirstQueue) Less(i, j int) bool {
+	if q[i].Score != q[j].Score {
+		return q[i].Score > q[j].Score
+	}
+	return q[i].Order < q[j].Order
+}
+func (q bestFirstQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *bestFirstQueue) Push(x any)   { *q = append(*q, x.(bestFirstEntry)) }
+func (q *bestFirstQueue) Pop() any {
+	old := *q
+	entry := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return entry
+}
+
+// targetProfile scores how similar a page is to the target, from the tokens of the target's
+// title, the tokens of the titles the target links to and the pages the target links to
+type targetProfile struct {
+	site        WikiSite
+	titleTokens []string
+	linkTokens  map[string]float64 // token -> frequency among the target's link titles, relative to the most frequent token
+	neighbors   map[string]struct{}
+}
+
+func newTargetProfile(site WikiSite, target string, targetLinks []string) targetProfile {
+	profile := targetProfile{
+		site:        site,
+		titleTokens: titleTokens(site.PageTitle(target)),
+		linkTokens:  make(map[string]float64),
+		neighbors:   make(map[string]struct{}, len(targetLinks)),
+	}
+
+	maxCount := 0.0
+	for _, link := range targetLinks {
+		profile.neighbors[link] = struct{}{}
+		for _, token := range titleTokens(site.PageTitle(link)) {
+			profile.linkTokens[token] += 1
+			maxCount = max(maxCount, profile.linkTokens[token])
+		}
+	}
+	for token := range profile.linkTokens {
+		profile.linkTokens[token] /= maxCount
+	}
+	return profile
+}
+
+func (p targetProfile) score(url string) float64 {
+	tokens := titleTokens(p.site.PageTitle(url))
+	score := 0.0
+	if len(tokens) > 0 {
+		shared, affinity := 0, 0.0
+		for _, token := range tokens {
+			if slices.Contains(p.titleTokens, token) {
+				shared += 1
+			}
+			affinity += p.linkTokens[token]
+		}
+		score += titleOverlapWeight*float64(shared)/float64(max(len(tokens), len(p.titleTokens))) + affinity/float64(len(tokens))
+	}
+	if _, ok := p.neighbors[url]; ok {
+		score += neighborWeight
+	}
+	return score
+}
+
+// titleTokens splits a title into lower case words without stopwords
+func titleTokens(title string) []string {
+	tokens := make([]string, 0)
+	for _, token := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(token) > 1 && !slices.Contains(titleStopwords, token) && !slices.Contains(tokens, token) {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// findBestFirstPaths expands the most promising pages first, with numWorkers pages expanded at once.
+// Links are scored by their similarity to the target minus a penalty for the steps taken, and every
+// page is expanded at most once, so a path is found through each page linking to the target.
+func (w WikiSteps) findBestFirstPaths(ctx context.Context, start string, target string, steps int, maxResults int, cache *linkCache, monitor *SearchMonitor) ([][]string, error) {
+	if start == target {
+		monitor.pathFound([]string{start})
+		return [][]string{{start}}, nil
+	}
+
+	w.log.Trace("Initializing best-first resources...")
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // signals the workers of an unfinished batch to exit
+	workerNames := util.RandomNames(w.numWorkers/100, w.numWorkers)
+
+	targetLinks, err := cache.Links(ctx, "profiler", target)
+	if err != nil {
+		return [][]string{}, fmt.Errorf("error when fetching the links of target %s; %w", target, err)
+	}
+	profile := newTargetProfile(w.site, target, targetLinks)
+
+	queue := &bestFirstQueue{{Path: []string{start}}}
+	visited := map[string]struct{}{start: {}}
+	order := 0
+	monitor.jobQueued(0)
+
+	results := make([][]string, 0)
+	for queue.Len() > 0 {
+		paths := make(map[string][]string, w.numWorkers)
+		batch := make([]string, 0, w.numWorkers)
+		for queue.Len() > 0 && len(batch) < w.numWorkers {
+			entry := heap.Pop(queue).(bestFirstEntry)
+			url := entry.Path[len(entry.Path)-1]
+			paths[url] = entry.Path
+			batch = append(batch, url)
+		}
+
+		enough := func(url string, links []string) bool {
+			if !slices.Contains(links, target) {
+				return false
+			}
+			path := append(slices.Clone(paths[url]), target)
+			results = append(results, path)
+			monitor.pathFound(path)
+			w.log.Debug("WikiSteps found a path", "steps", len(path)-1)
+			return maxResults > 0 && len(results) >= maxResults
+		}
+
+		w.log.Debug("WikiSteps is expanding the most promising pages", "pages", len(batch), "queued", queue.Len())
+		expanded, err := w.expandFrontier(ctx, workerNames, batch, forwardDirection, cache, monitor, enough)
+		if ctx.Err() != nil {
+			w.logSearchStopped(ctx)
+			return results, nil
+		}
+		if err != nil {
+			return results, fmt.Errorf("error when expanding the most promising pages; %w", err)
+		}
+		if maxResults > 0 && len(results) >= maxResults {
+			return results, nil
+		}
+
+		for _, url := range batch {
+			links, ok := expanded[url]
+			depth := len(paths[url])
+			if !ok || depth >= steps {
+				continue // unexpanded, or its links could only reach the target in too many steps
+			}
+			for _, link := range links {
+				if _, seen := visited[link]; seen || link == target {
+					continue
+				}
+				visited[link] = struct{}{}
+				order += 1
+				heap.Push(queue, bestFirstEntry{
+					Path:  append(slices.Clone(paths[url]), link),
+					Score: profile.score(link) - depthWeight*float64(depth),
+					Order: order,
+				})
+				monitor.jobQueued(depth)
+			}
+		}
+	}
+
+	w.log.Debug("WikiSteps best-first search finished", "paths", len(results))
+	return results, nil
+}
@@ -0,0 +1,94 @@
+package wikiSteps
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+)
+
+var topicPath []string = []string{page("Start"), page("Physics_history"), page("Quantum_physics"), page("Quantum_field"), page("Quantum_field_theory")}
+
+// topicLinkSource builds a wiki of randomly titled and linked pages, in which a chain of pages
+// sharing more and more words with the target leads from the start page to the target
+func topicLinkSource(numPages int, numLinks int) *MemoryLinkSource {
+	rng := rand.New(rand.NewPCG(1, 2))
+	random := make([]string, numPages)
+	for i := range random {
+		random[i] = page(fmt.Sprintf("Topic%d_Subject%d", i, rng.IntN(numPages)))
+	}
+	randomLinks := func() []string {
+		links := make([]string, numLinks)
+		for i := range links {
+			links[i] = random[rng.IntN(numPages)]
+		}
+		return links
+	}
+
+	pages := make(map[string][]string, numPages+len(topicPath))
+	for _, p := range random {
+		pages[p] = randomLinks()
+	}
+	for i, p := range topicPath[:len(topicPath)-1] {
+		pages[p] = append(randomLinks(), topicPath[i+1])
+	}
+	pages[page("Quantum_field_theory")] = []string{page("Particle_physics"), page("Quantum_mechanics"), page("Physics")}
+	return NewMemoryLinkSource(pages)
+}
+
+func TestTitleTokens(t *testing.T) {
+	tokens := titleTokens("History of the Quantum field theory (physics)")
+	if expected := []string{"history", "quantum", "field", "theory", "physics"}; !slices.Equal(tokens, expected) {
+		t.Errorf("Expected: %v Actual: %v", expected, tokens)
+	}
+}
+
+func TestFindValidPathsBestFirst(t *testing.T) {
+	source := topicLinkSource(2000, 20)
+	bestFirst := NewWikistepsServiceWithSource(nopLogger{}, testSite, source, 5, 10*time.Second, 4)
+	result, err := bestFirst.FindValidPaths(context.Background(), page("Start"), page("Quantum_field_theory"), 4, SearchOptions{Strategy: BestFirstStrategy, MaxResults: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(result.Paths) != 1 || !slices.Equal(result.Paths[0], topicPath) {
+		t.Errorf("Expected: %v Actual: %v", topicPath, result.Paths)
+	}
+
+	shortest, err := bestFirst.FindValidPaths(context.Background(), page("Start"), page("Quantum_field_theory"), 4, SearchOptions{Strategy: ShortestStrategy, MaxResults: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if result.CacheMisses >= shortest.CacheMisses {
+		t.Errorf("Expected best-first to fetch fewer pages than breadth-first, Actual: %d best-first and %d breadth-first fetches", result.CacheMisses, shortest.CacheMisses)
+	}
+
+	result, err = bestFirst.FindValidPaths(context.Background(), page("Start"), page("Quantum_field_theory"), 3, SearchOptions{Strategy: BestFirstStrategy})
+	if err != nil || len(result.Paths) != 0 {
+		t.Errorf("Expected: no paths within 3 steps Actual: %v (%v)", result.Paths, err)
+	}
+}
+
+func benchmarkStrategy(b *testing.B, strategy SearchStrategy) {
+	source := topicLinkSource(5000, 25)
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, source, 5, time.Minute, 8)
+	fetches := int64(0)
+	b.ResetTimer()
+	for range b.N {
+		result, err := service.FindValidPaths(context.Background(), page("Start"), page("Quantum_field_theory"), 4, SearchOptions{Strategy: strategy, MaxResults: 1})
+		if err != nil || len(result.Paths) == 0 {
+			b.Fatalf("Expected a path Actual: %v (%v)", result.Paths, err)
+		}
+		fetches += result.CacheMisses
+	}
+	b.ReportMetric(float64(fetches)/float64(b.N), "fetches/op")
+}
+
+func BenchmarkShortestStrategy(b *testing.B) {
+	benchmarkStrategy(b, ShortestStrategy)
+}
+
+func BenchmarkBestFirstStrategy(b *testing.B) {
+	benchmarkStrategy(b, BestFirstStrategy)
+}
@@ -0,0 +1,228 @@
+package wikiSteps
+
+import (
+	"app/rest_api/util"
+	"context"
+	"fmt"
+	"slices"
+)
+
+type frontierDirection int
+
+const (
+	forwardDirection  frontierDirection = iota // following the links on a page
+	backwardDirection                          // following the pages that link to a page
+)
+
+func (d frontierDirection) String() string {
+	if d == backwardDirection {
+		return "backward"
+	}
+	return "forward"
+}
+
+type frontierResult struct {
+	Url   string
+	Links []string
+	Err   error
+}
+
+// findBidirectionalPaths expands one breadth-first level at a time from whichever side has the
+// smaller frontier, and joins the two sides on the first level where they meet
+func (w WikiSteps) findBidirectionalPaths(ctx context.Context, start string, target string, steps int, maxResults int, cache *linkCache, monitor *SearchMonitor) ([][]string, bool, error) {
+	if start == target {
+		monitor.pathFound([]string{start})
+		return [][]string{{start}}, true, nil
+	}
+
+	w.log.Trace("Initializing bidirectional resources...")
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // signals the workers of an unfinished level to exit
+	workerNames := util.RandomNames(w.numWorkers/100, w.numWorkers)
+
+	fwdParents := map[string][]string{start: nil}   // page -> pages one step closer to start
+	bwdChildren := map[string][]string{target: nil} // page -> pages one step closer to target
+	fwdDepths := map[string]int{start: 0}
+	bwdDepths := map[string]int{target: 0}
+	fwdFrontier := []string{start}
+	bwdFrontier := []string{target}
+
+	for level := 0; level < steps; level++ {
+		if len(fwdFrontier) == 0 || len(bwdFrontier) == 0 {
+			w.log.Debug("WikiSteps dead end! A frontier has no pages left to expand")
+			break
+		}
+
+		direction, frontier := forwardDirection, fwdFrontier
+		if len(bwdFrontier) < len(fwdFrontier) {
+			direction, frontier = backwardDirection, bwdFrontier
+		}
+
+		w.log.Debug("WikiSteps is expanding a frontier", "direction", direction.String(), "pages", len(frontier), "level", level)
+		for range frontier {
+			monitor.jobQueued(level + 1)
+		}
+		expanded, err := w.expandFrontier(ctx, workerNames, frontier, direction, cache, monitor, nil)
+		if ctx.Err() != nil {
+			w.logSearchStopped(ctx)
+			return [][]string{}, false, nil
+		}
+		if err != nil {
+			return [][]string{}, false, fmt.Errorf("error when expanding %s frontier; %w", direction, err)
+		}
+
+		var meeting []string
+		if direction == forwardDirection {
+			fwdFrontier = mergeFrontier(expanded, fwdParents, fwdDepths)
+			meeting = meetingPages(fwdFrontier, fwdDepths, bwdDepths)
+		} else {
+			bwdFrontier = mergeFrontier(expanded, bwdChildren, bwdDepths)
+			meeting = meetingPages(bwdFrontier, fwdDepths, bwdDepths)
+		}
+
+		if len(meeting) > 0 {
+			w.log.Debug("WikiSteps frontiers met", "pages", len(meeting))
+			paths := joinFrontiers(meeting, fwdParents, bwdChildren, maxResults)
+			for _, path := range paths {
+				monitor.pathFound(path)
+			}
+			return paths, true, nil
+		}
+	}
+
+	return [][]string{}, false, nil
+}
+
+// expandFrontier returns early with the pages expanded so far once ctx is done or
+// the optional enough function returns true for an expanded page
+func (w WikiSteps) expandFrontier(ctx context.Context, workerNames []string, frontier []string, direction frontierDirection, cache *linkCache, monitor *SearchMonitor, enough func(url string, links []string) bool) (map[string][]string, error) {
+	urlCh := make(chan string, len(frontier))
+	for _, url := range frontier {
+		urlCh <- url
+	}
+	close(urlCh)
+
+	resultCh := make(chan frontierResult, len(frontier))
+	for i := 0; i < min(len(workerNames), len(frontier)); i += 1 {
+		go func(name string) {
+			for url := range urlCh {
+				if ctx.Err() != nil {
+					w.log.Debug("Worker received exit signal, closing...", "worker", name)
+					return
+				}
+				jobsInFlight.Inc()
+				links, err := w.fetchFrontierLinks(ctx, name, url, direction, cache)
+				jobsInFlight.Dec()
+				resultCh <- frontierResult{url, links, err}
+			}
+		}(workerNames[i])
+	}
+
+	expanded := make(map[string][]string, len(frontier))
+	for len(expanded) < len(frontier) {
+		select {
+		case <-ctx.Done():
+			return expanded, nil
+		case result := <-resultCh:
+			if result.Err != nil {
+				return expanded, result.Err
+			}
+			expanded[result.Url] = result.Links
+			monitor.jobCompleted()
+			if enough != nil && enough(result.Url, result.Links) {
+				return expanded, nil
+			}
+		}
+	}
+	return expanded, nil
+}
+
+func (w WikiSteps) fetchFrontierLinks(ctx context.Context, workerName string, url string, direction frontierDirection, cache *linkCache) ([]string, error) {
+	var urls []string
+	var err error
+	if direction == backwardDirection {
+		urls, err = cache.Backlinks(ctx, workerName, url)
+	} else {
+		urls, err = cache.Links(ctx, workerName, url)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("worker %s encountered an error when fetching %s links for URL %s; %w", workerName, direction, url, err)
+	}
+	return urls, nil
+}
+
+// mergeFrontier records every newly reached page with the pages it was reached from and returns them as the next frontier
+func mergeFrontier(expanded map[string][]string, previous map[string][]string, depths map[string]int) []string {
+	frontier := make([]string, 0)
+	for from, links := range expanded {
+		for _, url := range links {
+			depth, seen := depths[url]
+			if !seen {
+				depths[url] = depths[from] + 1
+				frontier = append(frontier, url)
+			} else if depth != depths[from]+1 {
+				continue // already reached on an earlier level
+			}
+			previous[url] = append(previous[url], from)
+		}
+	}
+	return frontier
+}
+
+// meetingPages returns the pages of the frontier reached from both sides with the shortest combined depth
+func meetingPages(frontier []string, fwdDepths map[string]int, bwdDepths map[string]int) []string {
+	meeting := make([]string, 0)
+	shortest := -1
+	for _, url := range frontier {
+		fwdDepth, fwdOk := fwdDepths[url]
+		bwdDepth, bwdOk := bwdDepths[url]
+		if !fwdOk || !bwdOk {
+			continue
+		}
+		if total := fwdDepth + bwdDepth; shortest < 0 || total < shortest {
+			shortest = total
+			meeting = []string{url}
+		} else if total == shortest {
+			meeting = append(meeting, url)
+		}
+	}
+	return meeting
+}
+
+// joinFrontiers joins the paths from start to every meeting page with the paths from there to target,
+// stopping at maxResults paths unless maxResults is 0
+func joinFrontiers(meeting []string, fwdParents map[string][]string, bwdChildren map[string][]string, maxResults int) [][]string {
+	results := make([][]string, 0)
+	for _, url := range meeting {
+		remaining := maxResults - len(results)
+		tails := walkPaths(url, bwdChildren, remaining)
+		for _, head := range walkPaths(url, fwdParents, remaining) {
+			for _, tail := range tails {
+				if maxResults > 0 && len(results) >= maxResults {
+					return results
+				}
+				joined := append(slices.Clone(head), tail[:len(tail)-1]...)
+				slices.Reverse(joined[len(head):])
+				results = append(results, joined)
+			}
+		}
+	}
+	return results
+}
+
+// walkPaths returns the paths from the root of the previous map to url, at most limit of them unless limit is 0 or less
+func walkPaths(url string, previous map[string][]string, limit int) [][]string {
+	if len(previous[url]) == 0 {
+		return [][]string{{url}}
+	}
+	paths := make([][]string, 0)
+	for _, p := range previous[url] {
+		for _, path := range walkPaths(p, previous, limit-len(paths)) {
+			paths = append(paths, append(path, url))
+			if limit > 0 && len(paths) >= limit {
+				return paths
+			}
+		}
+	}
+	return paths
+}
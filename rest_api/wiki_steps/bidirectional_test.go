@@ -0,0 +1,95 @@
+package wikiSteps
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+// diamondLinkSource has four shortest paths from S to T, all of them through the meeting page M
+func diamondLinkSource() *MemoryLinkSource {
+	return NewMemoryLinkSource(map[string][]string{
+		page("S"):  {page("A1"), page("A2")},
+		page("A1"): {page("M")},
+		page("A2"): {page("M")},
+		page("M"):  {page("B1"), page("B2")},
+		page("B1"): {page("T")},
+		page("B2"): {page("T")},
+		page("T"):  {},
+	})
+}
+
+func TestFindValidPathsBidirectionalMeeting(t *testing.T) {
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, diamondLinkSource(), 5, 10*time.Second, 4)
+	result, err := service.FindValidPaths(context.Background(), page("S"), page("T"), 4, SearchOptions{Strategy: BidirectionalStrategy})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := sortedPaths([][]string{
+		{page("S"), page("A1"), page("M"), page("B1"), page("T")},
+		{page("S"), page("A1"), page("M"), page("B2"), page("T")},
+		{page("S"), page("A2"), page("M"), page("B1"), page("T")},
+		{page("S"), page("A2"), page("M"), page("B2"), page("T")},
+	})
+	if paths := sortedPaths(result.Paths); !slices.Equal(paths, expected) || !result.Minimal {
+		t.Errorf("Expected: %v Actual: %v (minimal: %t)", expected, paths, result.Minimal)
+	}
+
+	result, err = service.FindValidPaths(context.Background(), page("S"), page("T"), 4, SearchOptions{Strategy: BidirectionalStrategy, MaxResults: 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(result.Paths) != 3 {
+		t.Errorf("Expected: 3 paths Actual: %v", result.Paths)
+	}
+}
+
+func TestJoinFrontiers(t *testing.T) {
+	fwdParents := map[string][]string{
+		"S":  nil,
+		"A1": {"S"},
+		"A2": {"S"},
+		"M":  {"A1", "A2"},
+	}
+	bwdChildren := map[string][]string{
+		"T":  nil,
+		"B1": {"T"},
+		"M":  {"B1"},
+	}
+
+	expected := []string{"S A1 M B1 T", "S A2 M B1 T"}
+	paths := joinFrontiers([]string{"M"}, fwdParents, bwdChildren, 0)
+	if actual := sortedPaths(paths); !slices.Equal(actual, expected) {
+		t.Errorf("Expected: %v Actual: %v", expected, actual)
+	}
+	if paths = joinFrontiers([]string{"M"}, fwdParents, bwdChildren, 1); len(paths) != 1 {
+		t.Errorf("Expected: 1 path Actual: %v", paths)
+	}
+
+	// the halves are not changed by joining them
+	if paths = joinFrontiers([]string{"M"}, fwdParents, bwdChildren, 0); !slices.Equal(sortedPaths(paths), expected) {
+		t.Errorf("Expected: %v Actual: %v", expected, sortedPaths(paths))
+	}
+}
+
+func TestWalkPathsLimit(t *testing.T) {
+	// every level doubles the number of paths to the last page
+	previous := map[string][]string{"0a": nil}
+	last := "0a"
+	for level := 1; level <= 20; level += 1 {
+		a, b := string(rune('a'+level))+"a", string(rune('a'+level))+"b"
+		previous[a] = []string{last}
+		previous[b] = []string{last}
+		previous["join"+a] = []string{a, b}
+		last = "join" + a
+	}
+
+	if paths := walkPaths(last, previous, 5); len(paths) != 5 {
+		t.Errorf("Expected: 5 paths Actual: %d", len(paths))
+	}
+	if paths := walkPaths("ba", previous, 0); len(paths) != 1 || !slices.Equal(paths[0], []string{"0a", "ba"}) {
+		t.Errorf("Expected: [[0a ba]] Actual: %v", paths)
+	}
+}
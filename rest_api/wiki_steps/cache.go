@@ -0,0 +1,193 @@
+package wikiSteps
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+type linkCacheEntry struct {
+	done  chan struct{} // closed once links and err are set
+	links []string
+	err   error
+}
+
+// linkCache wraps a LinkSource for the duration of a single search so that each page is only
+// fetched once, concurrent requests for a page being fetched wait for the first fetch to finish.
+// The returned link slices are shared between callers and must not be modified.
+// Pages and links are canonicalized, so every caller compares the same page identities.
+type linkCache struct {
+	source    LinkSource
+	site      WikiSite
+	policy    ExtractionPolicy
+	mu        sync.Mutex
+	links     map[string]*linkCacheEntry
+	backlinks map[string]*linkCacheEntry
+	aliases   map[string]string // redirect URL to the URL of the page it redirects to
+	resolver  RedirectResolver  // nil when the source does not know the redirects
+	resolved  map[string]string // link to the page it resolves to, guarded by mu
+	excluded  map[string]struct{}
+	hits      atomic.Int64
+	misses    atomic.Int64
+}
+
+// newLinkCache expects source to support the policy, see supportsExtractionPolicy
+func newLinkCache(source LinkSource, site WikiSite, policy ExtractionPolicy) *linkCache {
+	resolver, _ := source.(RedirectResolver)
+	return &linkCache{
+		source:    source,
+		site:      site,
+		policy:    policy,
+		links:     make(map[string]*linkCacheEntry),
+		backlinks: make(map[string]*linkCacheEntry),
+		aliases:   make(map[string]string),
+		resolver:  resolver,
+		resolved:  make(map[string]string),
+		excluded:  make(map[string]struct{}),
+	}
+}
+
+// exclude drops the pages from every list of links, it must be called before the cache is shared with the workers
+func (c *linkCache) exclude(pages ...string) {
+	for _, p := range pages {
+		c.excluded[c.canonical(p)] = struct{}{}
+	}
+}
+
+// addAliases must be called before the cache is shared with the workers
+func (c *linkCache) addAliases(page string, aliases ...string) {
+	for _, a := range aliases {
+		if a = c.site.CanonicalUrl(a); a != page {
+			c.aliases[a] = page
+		}
+	}
+}
+
+func (c *linkCache) canonical(url string) string {
+	url = c.site.CanonicalUrl(url)
+	if page, ok := c.aliases[url]; ok {
+		return page
+	}
+	return url
+}
+
+// canonicalLinks canonicalizes the links of page, dropping duplicates and links back to page
+func (c *linkCache) canonicalLinks(page string, links []string) []string {
+	if links == nil {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(links))
+	canonical := make([]string, 0, len(links))
+	for _, l := range links {
+		l = c.canonical(l)
+		if _, exists := seen[l]; exists || l == page {
+			continue
+		}
+		if _, excluded := c.excluded[l]; excluded {
+			continue
+		}
+		seen[l] = struct{}{}
+		canonical = append(canonical, l)
+	}
+	return canonical
+}
+
+// resolveLinks replaces the redirects among links by the pages they redirect to, every link is
+// only resolved once per search and all links unknown to the cache are resolved in one batch
+func (c *linkCache) resolveLinks(ctx context.Context, workerName string, links []string) ([]string, error) {
+	if c.resolver == nil || len(links) == 0 {
+		return links, nil
+	}
+
+	unknownSet := make(map[string]struct{})
+	c.mu.Lock()
+	for _, l := range links {
+		l = c.site.CanonicalUrl(l)
+		if _, ok := c.resolved[l]; !ok {
+			unknownSet[l] = struct{}{}
+		}
+	}
+	c.mu.Unlock()
+	unknown := slices.Collect(maps.Keys(unknownSet))
+
+	if len(unknown) > 0 {
+		redirects, err := c.resolver.ResolveAll(ctx, workerName, unknown)
+		if err != nil {
+			return nil, fmt.Errorf("worker %s encountered an error when resolving the redirects among %d links; %w", workerName, len(unknown), err)
+		}
+		c.mu.Lock()
+		for _, l := range unknown {
+			c.resolved[l] = c.site.CanonicalUrl(cmp.Or(redirects[l], l))
+		}
+		c.mu.Unlock()
+	}
+
+	resolved := make([]string, len(links))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, l := range links {
+		resolved[i] = c.resolved[c.site.CanonicalUrl(l)]
+	}
+	return resolved, nil
+}
+
+func (c *linkCache) Links(ctx context.Context, workerName string, url string) ([]string, error) {
+	if c.policy != AllLinks {
+		return c.get(ctx, c.links, c.policyLinks, workerName, url)
+	}
+	return c.get(ctx, c.links, c.source.Links, workerName, url)
+}
+
+func (c *linkCache) policyLinks(ctx context.Context, workerName string, url string) ([]string, error) {
+	source, ok := c.source.(PolicyLinkSource)
+	if !ok {
+		return nil, fmt.Errorf("link source does not support extraction policy %s", c.policy)
+	}
+	return source.PolicyLinks(ctx, workerName, url, c.policy)
+}
+
+func (c *linkCache) Backlinks(ctx context.Context, workerName string, url string) ([]string, error) {
+	return c.get(ctx, c.backlinks, c.source.Backlinks, workerName, url)
+}
+
+func (c *linkCache) Hits() int64 {
+	return c.hits.Load()
+}
+
+func (c *linkCache) Misses() int64 {
+	return c.misses.Load()
+}
+
+func (c *linkCache) get(ctx context.Context, entries map[string]*linkCacheEntry, fetch func(context.Context, string, string) ([]string, error), workerName string, url string) ([]string, error) {
+	url = c.canonical(url)
+	c.mu.Lock()
+	entry, exists := entries[url]
+	if !exists {
+		entry = &linkCacheEntry{done: make(chan struct{})}
+		entries[url] = entry
+	}
+	c.mu.Unlock()
+
+	if exists {
+		c.hits.Add(1)
+		select {
+		case <-entry.done:
+			return entry.links, entry.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	c.misses.Add(1)
+	links, err := fetch(ctx, workerName, url)
+	if err == nil {
+		links, err = c.resolveLinks(ctx, workerName, links)
+	}
+	entry.links, entry.err = c.canonicalLinks(url, links), err
+	close(entry.done)
+	return entry.links, entry.err
+}
@@ -0,0 +1,72 @@
+package wikiSteps
+
+import (
+	"context"
+	"fmt"
+)
+
+// RedirectResolver is implemented by link sources that know which pages are redirects
+type RedirectResolver interface {
+	// Resolve returns the URL of the page url redirects to, or url itself when it is no redirect
+	Resolve(ctx context.Context, workerName string, url string) (string, error)
+	// ResolveAll maps the redirects among urls to the URLs of the pages they redirect to,
+	// urls that are no redirect may be left out
+	ResolveAll(ctx context.Context, workerName string, urls []string) (map[string]string, error)
+	// Redirects returns the URLs of the redirect pages pointing to url
+	Redirects(ctx context.Context, workerName string, url string) ([]string, error)
+}
+
+// resolvePages canonicalizes the urls and follows their redirects
+func (w WikiSteps) resolvePages(ctx context.Context, urls []string) ([]string, error) {
+	pages := make([]string, len(urls))
+	for i, url := range urls {
+		pages[i] = w.site.CanonicalUrl(url)
+	}
+	resolver, ok := w.linkSource.(RedirectResolver)
+	if !ok || len(pages) == 0 {
+		return pages, nil
+	}
+
+	redirects, err := resolver.ResolveAll(ctx, "resolver", pages)
+	if err != nil {
+		return nil, err
+	}
+	for i, page := range pages {
+		if target, ok := redirects[page]; ok {
+			pages[i] = w.site.CanonicalUrl(target)
+		}
+	}
+	return pages, nil
+}
+
+// resolveSearchPages canonicalizes start and target, follows their redirects and registers the
+// redirects to the target with the cache so that links through them reach the target as well
+func (w WikiSteps) resolveSearchPages(ctx context.Context, start string, target string, cache *linkCache) (string, string, error) {
+	start, target = w.site.CanonicalUrl(start), w.site.CanonicalUrl(target)
+	resolver, ok := w.linkSource.(RedirectResolver)
+	if !ok {
+		return start, target, nil
+	}
+
+	resolvedStart, err := resolver.Resolve(ctx, "resolver", start)
+	if err != nil {
+		return start, target, fmt.Errorf("error when resolving start %s; %w", start, err)
+	}
+	resolvedTarget, err := resolver.Resolve(ctx, "resolver", target)
+	if err != nil {
+		return start, target, fmt.Errorf("error when resolving target %s; %w", target, err)
+	}
+	resolvedStart, resolvedTarget = w.site.CanonicalUrl(resolvedStart), w.site.CanonicalUrl(resolvedTarget)
+	if resolvedStart != start || resolvedTarget != target {
+		w.log.Debug("WikiSteps resolved the start and target", "start", start, "resolvedStart", resolvedStart, "target", target, "resolvedTarget", resolvedTarget)
+	}
+
+	redirects, err := resolver.Redirects(ctx, "resolver", resolvedTarget)
+	if err != nil {
+		return resolvedStart, resolvedTarget, fmt.Errorf("error when listing the redirects to target %s; %w", resolvedTarget, err)
+	}
+	w.log.Debug("WikiSteps found redirects to the target", "target", resolvedTarget, "redirects", len(redirects))
+	cache.addAliases(resolvedTarget, target)
+	cache.addAliases(resolvedTarget, redirects...)
+	return resolvedStart, resolvedTarget, nil
+}
@@ -0,0 +1,65 @@
+package wikiSteps
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFindValidPathsRedirects(t *testing.T) {
+	source := NewMemoryLinkSourceWithRedirects(map[string][]string{
+		page("A"): {page("Shortcut"), page("B")},
+		page("B"): {page("D#Section")},
+		page("D"): {},
+	}, map[string]string{
+		page("Shortcut"):    page("D"),
+		page("Start_alias"): page("A"),
+	})
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, source, 5, 10*time.Second, 4)
+
+	result, err := service.FindValidPaths(context.Background(), page("Start_alias"), page("d"), 2, SearchOptions{Strategy: ShortestStrategy})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := sortedPaths([][]string{{page("A"), page("D")}})
+	if paths := sortedPaths(result.Paths); !slices.Equal(paths, expected) {
+		t.Errorf("Expected: %v Actual: %v", expected, paths)
+	}
+
+	result, err = service.FindValidPaths(context.Background(), page("b"), page("Shortcut"), 1, SearchOptions{Strategy: ShortestStrategy})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected = sortedPaths([][]string{{page("B"), page("D")}})
+	if paths := sortedPaths(result.Paths); !slices.Equal(paths, expected) {
+		t.Errorf("Expected: %v Actual: %v", expected, paths)
+	}
+}
+
+func TestFindValidPathsIntermediateRedirects(t *testing.T) {
+	source := NewMemoryLinkSourceWithRedirects(map[string][]string{
+		page("A"): {page("Redirect_to_B"), page("B")},
+		page("B"): {page("C")},
+		page("C"): {page("T")},
+	}, map[string]string{
+		page("Redirect_to_B"): page("B"),
+	})
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, source, 5, 10*time.Second, 1)
+	tests := []struct {
+		options  SearchOptions
+		expected [][]string
+	}{
+		// the redirect and the page it redirects to are a single hop
+		{SearchOptions{Strategy: ShortestStrategy}, [][]string{{page("A"), page("B"), page("C"), page("T")}}},
+		{SearchOptions{Through: []string{page("Redirect_to_B")}}, [][]string{{page("A"), page("B"), page("C"), page("T")}}},
+		{SearchOptions{Strategy: ShortestStrategy, Exclude: []string{page("Redirect_to_B")}}, [][]string{}},
+	}
+
+	for _, test := range tests {
+		result, err := service.FindValidPaths(context.Background(), page("A"), page("T"), 3, test.options)
+		if expected := sortedPaths(test.expected); err != nil || !slices.Equal(sortedPaths(result.Paths), expected) {
+			t.Errorf("Expected: %v Actual: %v (%v)", expected, sortedPaths(result.Paths), err)
+		}
+	}
+}
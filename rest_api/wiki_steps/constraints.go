@@ -0,0 +1,123 @@
+package wikiSteps
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const categoryPrefix string = "Category:"
+
+// CategoryLinkSource is implemented by link sources that can list the articles of a category
+type CategoryLinkSource interface {
+	CategoryMembers(ctx context.Context, workerName string, category string) ([]string, error)
+}
+
+// categoryName strips the namespace from "Category:Philosophy"
+func categoryName(category string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(strings.TrimSpace(category), categoryPrefix), " ", "_")
+}
+
+// pathConstraints is the constraint layer of a forward search, the supervisor only creates jobs
+// for steps it allows and only returns the paths it accepts. Excluded pages are not part of it,
+// the link cache already drops them from every list of links.
+type pathConstraints struct {
+	through []string            // pages every path must pass through, in any order
+	members map[string]struct{} // pages of the allowed categories, nil allows every page
+}
+
+// newPathConstraints lists the members of the categories of options, pages are canonicalized
+// and the pages to pass through resolved like the links of the search
+func (w WikiSteps) newPathConstraints(ctx context.Context, options SearchOptions) (pathConstraints, error) {
+	var constraints pathConstraints
+	through, err := w.resolvePages(ctx, options.Through)
+	if err != nil {
+		return constraints, fmt.Errorf("error when resolving the pages to pass through; %w", err)
+	}
+	constraints.through = through
+	if len(options.Categories) == 0 {
+		return constraints, nil
+	}
+
+	source, ok := findSource[CategoryLinkSource](w.linkSource)
+	if !ok {
+		return constraints, fmt.Errorf("link source cannot list the articles of a category") // should never happen, see ValidateSearch
+	}
+	constraints.members = make(map[string]struct{})
+	for _, category := range options.Categories {
+		members, err := source.CategoryMembers(ctx, "constraints", categoryName(category))
+		if err != nil {
+			return constraints, fmt.Errorf("error when listing the articles of category %s; %w", category, err)
+		}
+		w.log.Debug("WikiSteps listed the articles of a category", "category", category, "articles", len(members))
+		for _, m := range members {
+			constraints.members[w.site.CanonicalUrl(m)] = struct{}{}
+		}
+	}
+	return constraints, nil
+}
+
+// allowsStep reports whether a job for path extended by url, with stepsRemaining steps left,
+// can still lead to a path accepted by allowsPath
+func (c pathConstraints) allowsStep(path []string, url string, stepsRemaining int) bool {
+	if c.members != nil {
+		if _, ok := c.members[url]; !ok {
+			return false
+		}
+	}
+
+	// every page still to pass through takes one step, and one more step reaches the target
+	missing := 0
+	for _, t := range c.through {
+		if t != url && !slices.Contains(path, t) {
+			missing += 1
+		}
+	}
+	return missing < stepsRemaining
+}
+
+// allowsPath reports whether a path reaching the target passed through every required page
+func (c pathConstraints) allowsPath(path []string) bool {
+	for _, t := range c.through {
+		if !slices.Contains(path, t) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w WikiSteps) validateConstraints(start string, target string, steps int, options SearchOptions) error {
+	if len(options.Through) == 0 && len(options.Categories) == 0 {
+		return nil
+	}
+	if options.Strategy != ForwardStrategy {
+		return newSearchError(ErrUnsupportedOption, "strategy", nil, "the %s strategy does not support through and categories", options.Strategy)
+	}
+
+	for _, url := range options.Through {
+		url = w.site.CanonicalUrl(url)
+		if !w.site.isValidWikiStepUrl(url) {
+			return newSearchError(ErrInvalidUrl, "through", nil, "page to pass through %s must be an article of %s", url, w.site.Host+w.site.ArticlePath)
+		}
+		if url == w.site.CanonicalUrl(start) || url == w.site.CanonicalUrl(target) {
+			return newSearchError(ErrInvalidOption, "through", nil, "every path passes through the start and target already")
+		}
+		if slices.ContainsFunc(options.Exclude, func(e string) bool { return w.site.CanonicalUrl(e) == url }) {
+			return newSearchError(ErrInvalidOption, "through", nil, "page to pass through %s is excluded", url)
+		}
+	}
+	if len(options.Through) >= steps {
+		return newSearchError(ErrInvalidOption, "through", nil, "passing through %d pages takes more than %d steps", len(options.Through), steps)
+	}
+
+	for _, category := range options.Categories {
+		if categoryName(category) == "" {
+			return newSearchError(ErrInvalidOption, "categories", nil, "categories cannot be empty")
+		}
+	}
+	if _, ok := findSource[CategoryLinkSource](w.linkSource); len(options.Categories) > 0 && !ok {
+		return newSearchError(ErrUnsupportedOption, "categories", nil, "link source cannot list the articles of a category")
+	}
+	return nil
+}
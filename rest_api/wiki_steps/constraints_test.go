@@ -0,0 +1,103 @@
+package wikiSteps
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func constraintsLinkSource() *MemoryLinkSource {
+	source := NewMemoryLinkSource(map[string][]string{
+		page("S"): {page("X"), page("Y"), page("Z")},
+		page("X"): {page("T"), page("Y")},
+		page("Y"): {page("T")},
+		page("Z"): {page("Y"), page("T")},
+	})
+	source.AddCategory("Letters", page("X"), page("Y"))
+	return source
+}
+
+func TestFindValidPathsConstraints(t *testing.T) {
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, constraintsLinkSource(), 5, 10*time.Second, 1)
+	tests := []struct {
+		options  SearchOptions
+		expected [][]string
+	}{
+		{SearchOptions{Through: []string{page("Y")}}, [][]string{
+			{page("S"), page("X"), page("Y"), page("T")},
+			{page("S"), page("Y"), page("T")},
+			{page("S"), page("Z"), page("Y"), page("T")},
+		}},
+		{SearchOptions{Through: []string{page("X"), page("Y")}}, [][]string{
+			{page("S"), page("X"), page("Y"), page("T")},
+		}},
+		{SearchOptions{Categories: []string{"Category:Letters"}}, [][]string{
+			{page("S"), page("X"), page("T")},
+			{page("S"), page("X"), page("Y"), page("T")},
+			{page("S"), page("Y"), page("T")},
+		}},
+		{SearchOptions{Through: []string{page("Y")}, Categories: []string{"Letters"}, Exclude: []string{page("X")}}, [][]string{
+			{page("S"), page("Y"), page("T")},
+		}},
+	}
+
+	// each forward search waits for its idle worker, so the searches run at once
+	var wg sync.WaitGroup
+	for _, test := range tests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := service.FindValidPaths(context.Background(), page("S"), page("T"), 3, test.options)
+			if expected := sortedPaths(test.expected); err != nil || !slices.Equal(sortedPaths(result.Paths), expected) {
+				t.Errorf("Expected: %v Actual: %v (%v)", expected, sortedPaths(result.Paths), err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestPathConstraintsAllowsStep(t *testing.T) {
+	constraints := pathConstraints{through: []string{page("X"), page("Y")}}
+	if !constraints.allowsStep([]string{page("S")}, page("X"), 2) {
+		t.Errorf("Expected: Y to be reachable within the 2 remaining steps")
+	}
+	if constraints.allowsStep([]string{page("S")}, page("Z"), 2) {
+		t.Errorf("Expected: X and Y not to be reachable within the 2 remaining steps")
+	}
+	if !constraints.allowsPath([]string{page("S"), page("Y"), page("X"), page("T")}) || constraints.allowsPath([]string{page("S"), page("X"), page("T")}) {
+		t.Errorf("Expected: only the path through X and Y to be allowed")
+	}
+}
+
+func TestValidateConstraints(t *testing.T) {
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, constraintsLinkSource(), 5, 10*time.Second, 4)
+	tests := []struct {
+		options SearchOptions
+		kind    error
+		field   string
+	}{
+		{SearchOptions{Strategy: ShortestStrategy, Through: []string{page("Y")}}, ErrUnsupportedOption, "strategy"},
+		{SearchOptions{Through: []string{"https://example.org/wiki/Y"}}, ErrInvalidUrl, "through"},
+		{SearchOptions{Through: []string{page("T")}}, ErrInvalidOption, "through"},
+		{SearchOptions{Through: []string{page("Y")}, Exclude: []string{page("Y")}}, ErrInvalidOption, "through"},
+		{SearchOptions{Through: []string{page("X"), page("Y"), page("Z")}}, ErrInvalidOption, "through"},
+		{SearchOptions{Categories: []string{"Category:"}}, ErrInvalidOption, "categories"},
+	}
+
+	for _, test := range tests {
+		var searchErr *SearchError
+		err := service.ValidateSearch(page("S"), page("T"), 3, test.options)
+		if !errors.Is(err, test.kind) || !errors.As(err, &searchErr) || searchErr.Field != test.field {
+			t.Errorf("Expected: %v of %s Actual: %v", test.kind, test.field, err)
+		}
+	}
+
+	// the html link source cannot list the articles of a category
+	service = NewWikistepsServiceWithSource(nopLogger{}, testSite, NewHtmlDirLinkSource(nopLogger{}, testSite, t.TempDir()), 5, 10*time.Second, 4)
+	if err := service.ValidateSearch(page("S"), page("T"), 3, SearchOptions{Categories: []string{"Letters"}}); !errors.Is(err, ErrUnsupportedOption) {
+		t.Errorf("Expected: %v Actual: %v", ErrUnsupportedOption, err)
+	}
+}
@@ -0,0 +1,298 @@
+package wikiSteps
+
+import (
+	"app/rest_api/logging"
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	graphFileMagic   string = "WSGRAPH2"
+	graphFileMagicV1 string = "WSGRAPH1" // written before redirects were part of the graph
+)
+
+// GraphStats describes a link graph written by ImportPagelinksDump
+type GraphStats struct {
+	Pages     int
+	Links     int
+	Redirects int
+}
+
+// ImportPagelinksDump builds a link graph of the namespace 0 pages of a Wikipedia page.sql dump
+// and writes it to out. The pagelinks.sql dump may either use the classic (pl_from, pl_namespace,
+// pl_title, pl_from_namespace) rows or the (pl_from, pl_from_namespace, pl_target_id) rows, in
+// which case the linktarget.sql dump is required to resolve the targets.
+// Links to redirect pages are replaced by links to the pages the redirect.sql dump says they
+// redirect to, and redirect pages have no links of their own. Without a redirect dump the
+// links to redirect pages are dropped.
+func ImportPagelinksDump(log logging.Logger, pageDump io.Reader, pagelinksDump io.Reader, linktargetDump io.Reader, redirectDump io.Reader, out io.Writer) (GraphStats, error) {
+	titles := make([]string, 0)
+	titleIndex := make(map[string]uint32)
+	pageIndex := make(map[int64]uint32)
+	redirectPages := make(map[uint32]struct{})
+
+	log.Info("Importing pages from the page dump...")
+	err := parseSqlInserts(pageDump, func(fields []string) error {
+		if len(fields) < 4 || fields[1] != "0" {
+			return nil
+		}
+		id, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid page_id '%s'; %w", fields[0], err)
+		}
+		if fields[3] == "1" { // page_is_redirect
+			redirectPages[uint32(len(titles))] = struct{}{}
+		}
+		pageIndex[id] = uint32(len(titles))
+		titleIndex[fields[2]] = uint32(len(titles))
+		titles = append(titles, fields[2])
+		return nil
+	})
+	if err != nil {
+		return GraphStats{}, fmt.Errorf("error when importing the page dump; %w", err)
+	}
+	log.Info("Imported pages", "pages", len(titles)-len(redirectPages), "redirects", len(redirectPages))
+
+	redirects := make(map[uint32]uint32) // redirect page -> page it redirects to
+	if redirectDump != nil {
+		log.Info("Importing redirects from the redirect dump...")
+		err = parseSqlInserts(redirectDump, func(fields []string) error {
+			// rd_from, rd_namespace, rd_title, rd_interwiki, rd_fragment, interwiki redirects leave the wiki
+			if len(fields) < 4 || fields[1] != "0" || (fields[3] != "" && fields[3] != "NULL") {
+				return nil
+			}
+			id, err := strconv.ParseInt(fields[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid rd_from '%s'; %w", fields[0], err)
+			}
+			from, ok := pageIndex[id]
+			if _, isRedirect := redirectPages[from]; !ok || !isRedirect {
+				return nil
+			}
+			if to, ok := titleIndex[fields[2]]; ok {
+				redirects[from] = to
+			}
+			return nil
+		})
+		if err != nil {
+			return GraphStats{}, fmt.Errorf("error when importing the redirect dump; %w", err)
+		}
+	} else if len(redirectPages) > 0 {
+		log.Info("Links to redirect pages are dropped, no redirect dump was given", "redirects", len(redirectPages))
+	}
+	// MediaWiki does not follow double redirects either
+	for from, to := range redirects {
+		if _, isRedirect := redirectPages[to]; isRedirect || from == to {
+			delete(redirects, from)
+		}
+	}
+	log.Info("Imported redirects", "redirects", len(redirects))
+
+	targetIndex := make(map[int64]uint32)
+	if linktargetDump != nil {
+		log.Info("Importing link targets from the linktarget dump...")
+		err = parseSqlInserts(linktargetDump, func(fields []string) error {
+			if len(fields) < 3 || fields[1] != "0" {
+				return nil
+			}
+			id, err := strconv.ParseInt(fields[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid lt_id '%s'; %w", fields[0], err)
+			}
+			if i, ok := titleIndex[fields[2]]; ok {
+				targetIndex[id] = i
+			}
+			return nil
+		})
+		if err != nil {
+			return GraphStats{}, fmt.Errorf("error when importing the linktarget dump; %w", err)
+		}
+	}
+
+	log.Info("Importing links from the pagelinks dump...")
+	adjacency := make([][]uint32, len(titles))
+	err = parseSqlInserts(pagelinksDump, func(fields []string) error {
+		var fromId string
+		var to uint32
+		var ok bool
+		switch len(fields) {
+		case 4: // pl_from, pl_namespace, pl_title, pl_from_namespace
+			if fields[1] != "0" || fields[3] != "0" {
+				return nil
+			}
+			fromId = fields[0]
+			to, ok = titleIndex[fields[2]]
+		case 3: // pl_from, pl_from_namespace, pl_target_id
+			if fields[1] != "0" {
+				return nil
+			}
+			if linktargetDump == nil {
+				return fmt.Errorf("the pagelinks dump references link targets, a linktarget dump is required")
+			}
+			targetId, err := strconv.ParseInt(fields[2], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid pl_target_id '%s'; %w", fields[2], err)
+			}
+			fromId = fields[0]
+			to, ok = targetIndex[targetId]
+		default:
+			return fmt.Errorf("unexpected pagelinks row with %d fields", len(fields))
+		}
+		if !ok {
+			return nil // link to a page missing from the page dump
+		}
+		if _, isRedirect := redirectPages[to]; isRedirect {
+			if to, ok = redirects[to]; !ok {
+				return nil // redirect to a page missing from the page dump
+			}
+		}
+
+		id, err := strconv.ParseInt(fromId, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid pl_from '%s'; %w", fromId, err)
+		}
+		from, ok := pageIndex[id]
+		if _, isRedirect := redirectPages[from]; ok && !isRedirect && from != to {
+			adjacency[from] = append(adjacency[from], to)
+		}
+		return nil
+	})
+	if err != nil {
+		return GraphStats{}, fmt.Errorf("error when importing the pagelinks dump; %w", err)
+	}
+
+	stats, err := writeGraph(out, titles, adjacency, redirects)
+	stats.Pages -= len(redirectPages)
+	if err != nil {
+		return stats, fmt.Errorf("error when writing the link graph; %w", err)
+	}
+	log.Info("Wrote a link graph", "pages", stats.Pages, "links", stats.Links, "redirects", stats.Redirects)
+	return stats, nil
+}
+
+// writeGraph writes the magic, the page count, every title, the sorted link targets of every
+// page as delta encoded uvarints and finally the redirect count and every redirect as a pair
+// of the redirect page and the page it redirects to
+func writeGraph(out io.Writer, titles []string, adjacency [][]uint32, redirects map[uint32]uint32) (GraphStats, error) {
+	stats := GraphStats{Pages: len(titles), Redirects: len(redirects)}
+	w := bufio.NewWriter(out)
+	buf := make([]byte, 0, 64)
+
+	w.WriteString(graphFileMagic)
+	buf = binary.AppendUvarint(buf[:0], uint64(len(titles)))
+	w.Write(buf)
+	for _, t := range titles {
+		buf = binary.AppendUvarint(buf[:0], uint64(len(t)))
+		w.Write(buf)
+		w.WriteString(t)
+	}
+
+	for _, links := range adjacency {
+		slices.Sort(links)
+		links = slices.Compact(links)
+		stats.Links += len(links)
+
+		buf = binary.AppendUvarint(buf[:0], uint64(len(links)))
+		previous := uint32(0)
+		for _, to := range links {
+			buf = binary.AppendUvarint(buf, uint64(to-previous))
+			previous = to
+		}
+		if _, err := w.Write(buf); err != nil {
+			return stats, err
+		}
+	}
+
+	buf = binary.AppendUvarint(buf[:0], uint64(len(redirects)))
+	for _, from := range slices.Sorted(maps.Keys(redirects)) {
+		buf = binary.AppendUvarint(buf, uint64(from))
+		buf = binary.AppendUvarint(buf, uint64(redirects[from]))
+	}
+	if _, err := w.Write(buf); err != nil {
+		return stats, err
+	}
+	return stats, w.Flush()
+}
+
+// parseSqlInserts calls fn with the fields of every row of the INSERT statements of a mysqldump,
+// string fields are unquoted and unescaped
+func parseSqlInserts(r io.Reader, fn func(fields []string) error) error {
+	br := bufio.NewReaderSize(r, 1<<20)
+	for {
+		line, err := br.ReadString('\n')
+		if strings.HasPrefix(line, "INSERT INTO") {
+			if i := strings.Index(line, " VALUES "); i >= 0 {
+				if err := parseSqlRows(line[i+len(" VALUES "):], fn); err != nil {
+					return err
+				}
+			}
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+func parseSqlRows(values string, fn func(fields []string) error) error {
+	fields := make([]string, 0, 16)
+	var field strings.Builder
+	inRow, inString := false, false
+
+	for i := 0; i < len(values); i += 1 {
+		c := values[i]
+		switch {
+		case inString:
+			if c == '\\' && i+1 < len(values) {
+				i += 1
+				field.WriteByte(unescapeSqlByte(values[i]))
+			} else if c == '\'' {
+				inString = false
+			} else {
+				field.WriteByte(c)
+			}
+		case !inRow:
+			if c == '(' {
+				inRow = true
+				fields = fields[:0]
+			}
+		case c == '\'':
+			inString = true
+		case c == ',':
+			fields = append(fields, field.String())
+			field.Reset()
+		case c == ')':
+			fields = append(fields, field.String())
+			field.Reset()
+			inRow = false
+			if err := fn(fields); err != nil {
+				return err
+			}
+		default:
+			field.WriteByte(c)
+		}
+	}
+	return nil
+}
+
+func unescapeSqlByte(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case '0':
+		return 0
+	default:
+		return c
+	}
+}
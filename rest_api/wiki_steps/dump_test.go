@@ -0,0 +1,144 @@
+package wikiSteps
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func importFixtureGraph(t *testing.T, pagelinks string, linktarget string, redirect string) (*GraphLinkSource, GraphStats) {
+	open := func(name string) io.Reader {
+		content, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return bytes.NewReader(content)
+	}
+
+	var linktargetDump io.Reader
+	if linktarget != "" {
+		linktargetDump = open(linktarget)
+	}
+	var redirectDump io.Reader
+	if redirect != "" {
+		redirectDump = open(redirect)
+	}
+	var graphFile bytes.Buffer
+	stats, err := ImportPagelinksDump(nopLogger{}, open("page.sql"), open(pagelinks), linktargetDump, redirectDump, &graphFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	graph, err := ReadGraphLinkSource(testSite, &graphFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return graph, stats
+}
+
+func TestImportPagelinksDump(t *testing.T) {
+	graph, stats := importFixtureGraph(t, "pagelinks.sql", "", "redirect.sql")
+	if expected := (GraphStats{Pages: 6, Links: 10, Redirects: 2}); stats != expected {
+		t.Errorf("Expected: %+v Actual: %+v", expected, stats)
+	}
+
+	// the links to the redirects MT and Chomsky point to the pages they redirect to
+	links, err := graph.Links(context.Background(), "test", page("Machine_translation"))
+	if expected := []string{page("Computational_linguistics"), page("Noam_Chomsky"), page("C%2B%2B")}; err != nil || !slices.Equal(links, expected) {
+		t.Errorf("Expected: %v Actual: %v (%v)", expected, links, err)
+	}
+
+	links, err = graph.Links(context.Background(), "test", page("Noam_Chomsky"))
+	if expected := []string{page("Linguistics"), page("Chomsky%27s_hierarchy")}; err != nil || !slices.Equal(links, expected) {
+		t.Errorf("Expected: %v Actual: %v (%v)", expected, links, err)
+	}
+
+	links, err = graph.Links(context.Background(), "test", page("Chomsky%27s_hierarchy"))
+	if expected := []string{page("Machine_translation"), page("Noam_Chomsky")}; err != nil || !slices.Equal(links, expected) {
+		t.Errorf("Expected: %v Actual: %v (%v)", expected, links, err)
+	}
+
+	backlinks, err := graph.Backlinks(context.Background(), "test", page("Noam_Chomsky"))
+	if expected := []string{page("Machine_translation"), page("Computational_linguistics"), page("Chomsky%27s_hierarchy")}; err != nil || !slices.Equal(backlinks, expected) {
+		t.Errorf("Expected: %v Actual: %v (%v)", expected, backlinks, err)
+	}
+
+	if links, err = graph.Links(context.Background(), "test", page("Unknown")); err != nil || len(links) != 0 {
+		t.Errorf("Expected: [] Actual: %v (%v)", links, err)
+	}
+}
+
+func TestImportPagelinksDumpWithLinkTargets(t *testing.T) {
+	graph, stats := importFixtureGraph(t, "pagelinks_linktarget.sql", "linktarget.sql", "")
+	if expected := (GraphStats{Pages: 6, Links: 5}); stats != expected {
+		t.Errorf("Expected: %+v Actual: %+v", expected, stats)
+	}
+
+	backlinks, err := graph.Backlinks(context.Background(), "test", page("Linguistics"))
+	if expected := []string{page("Computational_linguistics"), page("Noam_Chomsky")}; err != nil || !slices.Equal(backlinks, expected) {
+		t.Errorf("Expected: %v Actual: %v (%v)", expected, backlinks, err)
+	}
+
+	pages := bytes.NewBufferString("INSERT INTO `page` VALUES (10,0,'A',0);\n")
+	pagelinks := bytes.NewBufferString("INSERT INTO `pagelinks` VALUES (10,0,1);\n")
+	if _, err = ImportPagelinksDump(nopLogger{}, pages, pagelinks, nil, nil, io.Discard); err == nil {
+		t.Errorf("Expected an error for link target rows without a linktarget dump")
+	}
+}
+
+func TestGraphLinkSourceRedirects(t *testing.T) {
+	graph, _ := importFixtureGraph(t, "pagelinks.sql", "", "redirect.sql")
+
+	resolved, err := graph.ResolveAll(context.Background(), "test", []string{page("MT"), page("Chomsky"), page("Linguistics")})
+	expected := map[string]string{page("MT"): page("Machine_translation"), page("Chomsky"): page("Noam_Chomsky")}
+	if err != nil || !maps.Equal(resolved, expected) {
+		t.Errorf("Expected: %v Actual: %v (%v)", expected, resolved, err)
+	}
+	redirects, err := graph.Redirects(context.Background(), "test", page("Noam_Chomsky"))
+	if expected := []string{page("Chomsky")}; err != nil || !slices.Equal(redirects, expected) {
+		t.Errorf("Expected: %v Actual: %v (%v)", expected, redirects, err)
+	}
+
+	// the redirects are no extra step, and a search between them runs between the pages they redirect to
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, graph, 5, 10*time.Second, 4)
+	result, err := service.FindValidPaths(context.Background(), page("MT"), page("Chomsky"), 1, SearchOptions{Strategy: ShortestStrategy})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := sortedPaths([][]string{{page("Machine_translation"), page("Noam_Chomsky")}}); !slices.Equal(sortedPaths(result.Paths), expected) {
+		t.Errorf("Expected: %v Actual: %v", expected, sortedPaths(result.Paths))
+	}
+
+	// links to redirects are dropped without a redirect dump
+	graph, stats := importFixtureGraph(t, "pagelinks.sql", "", "")
+	if expected := (GraphStats{Pages: 6, Links: 8}); stats != expected {
+		t.Errorf("Expected: %+v Actual: %+v", expected, stats)
+	}
+	if resolved, err := graph.Resolve(context.Background(), "test", page("MT")); err != nil || resolved != page("MT") {
+		t.Errorf("Expected: %v Actual: %v (%v)", page("MT"), resolved, err)
+	}
+}
+
+func TestFindValidPathsGraph(t *testing.T) {
+	graph, _ := importFixtureGraph(t, "pagelinks.sql", "", "redirect.sql")
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, graph, 5, 10*time.Second, 4)
+
+	result, err := service.FindValidPaths(context.Background(), page("Machine_translation"), page("Linguistics"), 3, SearchOptions{Strategy: ShortestStrategy})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := sortedPaths([][]string{
+		{page("Machine_translation"), page("Computational_linguistics"), page("Linguistics")},
+		{page("Machine_translation"), page("Noam_Chomsky"), page("Linguistics")}, // through the redirect Chomsky
+	})
+	if paths := sortedPaths(result.Paths); !slices.Equal(paths, expected) {
+		t.Errorf("Expected: %v Actual: %v", expected, paths)
+	}
+}
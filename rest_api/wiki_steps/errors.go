@@ -0,0 +1,59 @@
+package wikiSteps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// sentinel errors of a search, the errors returned by WikiSteps wrap one of them in a *SearchError
+var (
+	ErrInvalidUrl        = errors.New("invalid url")
+	ErrStepsOutOfRange   = errors.New("steps out of range")
+	ErrInvalidOption     = errors.New("invalid search option")
+	ErrUnsupportedOption = errors.New("unsupported search option")
+	ErrUpstreamFailure   = errors.New("upstream failure")
+	ErrTimeout           = errors.New("search timed out")
+	ErrCancelled         = errors.New("search was cancelled")
+)
+
+// SearchError describes why a search cannot be run or failed. Kind is one of the sentinel errors
+// and Field the search option at fault, empty when the error is not caused by an option.
+type SearchError struct {
+	Kind    error
+	Field   string
+	Message string
+	Cause   error
+}
+
+func newSearchError(kind error, field string, cause error, format string, args ...any) *SearchError {
+	return &SearchError{
+		Kind:    kind,
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+		Cause:   cause,
+	}
+}
+
+func (e *SearchError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s; %s", e.Message, e.Cause.Error())
+	}
+	return e.Message
+}
+
+// Unwrap allows errors.Is to match both the kind and the cause
+func (e *SearchError) Unwrap() []error {
+	return []error{e.Kind, e.Cause}
+}
+
+// searchFailed wraps an error encountered while a search was running, ctx is the context of the search
+func searchFailed(ctx context.Context, err error, format string, args ...any) *SearchError {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return newSearchError(ErrTimeout, "", err, format, args...)
+	}
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return newSearchError(ErrCancelled, "", err, format, args...)
+	}
+	return newSearchError(ErrUpstreamFailure, "", err, format, args...)
+}
@@ -0,0 +1,98 @@
+package wikiSteps
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// failingLinkSource fails every lookup, or blocks until ctx is done when block is set
+type failingLinkSource struct {
+	block bool
+}
+
+func (s failingLinkSource) Links(ctx context.Context, workerName string, url string) ([]string, error) {
+	if s.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return nil, errors.New("status 503")
+}
+
+func (s failingLinkSource) Backlinks(ctx context.Context, workerName string, url string) ([]string, error) {
+	return s.Links(ctx, workerName, url)
+}
+
+// blockingResolver blocks every redirect lookup until ctx is done, resolving is closed by the first one
+type blockingResolver struct {
+	failingLinkSource
+	resolving chan struct{}
+	once      *sync.Once
+}
+
+func newBlockingResolver() blockingResolver {
+	return blockingResolver{failingLinkSource{block: true}, make(chan struct{}), &sync.Once{}}
+}
+
+func (s blockingResolver) Resolve(ctx context.Context, workerName string, url string) (string, error) {
+	s.once.Do(func() { close(s.resolving) })
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func (s blockingResolver) ResolveAll(ctx context.Context, workerName string, urls []string) (map[string]string, error) {
+	_, err := s.Resolve(ctx, workerName, "")
+	return nil, err
+}
+
+func (s blockingResolver) Redirects(ctx context.Context, workerName string, url string) ([]string, error) {
+	_, err := s.Resolve(ctx, workerName, url)
+	return nil, err
+}
+
+func TestSearchErrors(t *testing.T) {
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, testLinkSource(), 5, 10*time.Second, 4)
+	tests := []struct {
+		start  string
+		target string
+		steps  int
+		kind   error
+		field  string
+	}{
+		{"https://example.org/wiki/A", page("D"), 3, ErrInvalidUrl, "start"},
+		{page("A"), page("Main_Page"), 3, ErrInvalidUrl, "target"},
+		{page("A"), page("D"), 6, ErrStepsOutOfRange, "steps"},
+		{page("A"), page("D"), -1, ErrStepsOutOfRange, "steps"},
+	}
+	for _, test := range tests {
+		_, err := service.FindValidPaths(context.Background(), test.start, test.target, test.steps, SearchOptions{Strategy: ShortestStrategy})
+		var searchErr *SearchError
+		if !errors.Is(err, test.kind) || !errors.As(err, &searchErr) || searchErr.Field != test.field {
+			t.Errorf("Expected: %v of %s Actual: %v", test.kind, test.field, err)
+		}
+	}
+
+	failing := NewWikistepsServiceWithSource(nopLogger{}, testSite, failingLinkSource{}, 5, 10*time.Second, 4)
+	if _, err := failing.FindValidPaths(context.Background(), page("A"), page("D"), 3, SearchOptions{Strategy: ShortestStrategy}); !errors.Is(err, ErrUpstreamFailure) {
+		t.Errorf("Expected: %v Actual: %v", ErrUpstreamFailure, err)
+	}
+
+	blocking := NewWikistepsServiceWithSource(nopLogger{}, testSite, failingLinkSource{block: true}, 5, 50*time.Millisecond, 4)
+	result, err := blocking.FindValidPaths(context.Background(), page("A"), page("D"), 3, SearchOptions{Strategy: ShortestStrategy})
+	if !errors.Is(err, ErrTimeout) || !result.TimedOut {
+		t.Errorf("Expected: %v Actual: %v (timed out: %t)", ErrTimeout, err, result.TimedOut)
+	}
+
+	resolver := newBlockingResolver()
+	resolving := NewWikistepsServiceWithSource(nopLogger{}, testSite, resolver, 5, 10*time.Second, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-resolver.resolving
+		cancel()
+	}()
+	if _, err = resolving.FindValidPaths(ctx, page("A"), page("D"), 3, SearchOptions{Strategy: ShortestStrategy}); !errors.Is(err, ErrCancelled) {
+		t.Errorf("Expected: %v Actual: %v", ErrCancelled, err)
+	}
+}
@@ -0,0 +1,123 @@
+package wikiSteps
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const contentTextId string = "mw-content-text"
+
+type ExtractionPolicy int
+
+const (
+	AllLinks     ExtractionPolicy = iota // every link on the page
+	ContentLinks                         // only links in the main content, #mw-content-text
+	ArticleLinks                         // only links in the main content outside of navigation boxes, infoboxes and references
+	LeadLinks                            // only article links of the lead section, before the first heading
+)
+
+var (
+	// elements with one of these classes are boilerplate rather than article text
+	boilerplateClasses []string = []string{
+		"navbox", "vertical-navbox", "navbox-styles", "infobox", "sidebar", "metadata", "ambox",
+		"hatnote", "reflist", "references", "mw-references-wrap", "reference", "mw-editsection",
+	}
+	// sections with one of these heading ids are made up of boilerplate links
+	boilerplateSectionIds []string = []string{
+		"See_also", "Notes", "References", "Citations", "Sources", "Bibliography", "Further_reading", "External_links",
+	}
+)
+
+// PolicyLinkSource is implemented by link sources that can limit the links of a page to the
+// parts of the article selected by an ExtractionPolicy
+type PolicyLinkSource interface {
+	PolicyLinks(ctx context.Context, workerName string, url string, policy ExtractionPolicy) ([]string, error)
+}
+
+func supportsExtractionPolicy(source LinkSource, policy ExtractionPolicy) bool {
+	if policy == AllLinks {
+		return true
+	}
+	_, ok := findSource[PolicyLinkSource](source)
+	return ok
+}
+
+func ParseExtractionPolicy(s string) (ExtractionPolicy, error) {
+	switch strings.ToLower(s) {
+	case "", "all":
+		return AllLinks, nil
+	case "content":
+		return ContentLinks, nil
+	case "article":
+		return ArticleLinks, nil
+	case "lead":
+		return LeadLinks, nil
+	default:
+		return AllLinks, fmt.Errorf("unknown extraction policy '%s'", s)
+	}
+}
+
+func (p ExtractionPolicy) String() string {
+	switch p {
+	case AllLinks:
+		return "all"
+	case ContentLinks:
+		return "content"
+	case ArticleLinks:
+		return "article"
+	case LeadLinks:
+		return "lead"
+	default:
+		return fmt.Sprintf("ExtractionPolicy(%d)", int(p))
+	}
+}
+
+// linkFilter decides which links of a page are extracted under a policy, nodes have to be
+// visited in document order since sections are tracked through their headings
+type linkFilter struct {
+	policy      ExtractionPolicy
+	inSection   bool // past the first heading of the content
+	skipSection bool // inside a boilerplate section
+}
+
+// scope returns the element links are extracted from, nil when the page has no main content
+func (f *linkFilter) scope(root *html.Node) *html.Node {
+	if f.policy == AllLinks {
+		return root
+	}
+	return findElementById(root, contentTextId)
+}
+
+// visit reports whether the links below n are extracted
+func (f *linkFilter) visit(n *html.Node) bool {
+	if f.policy == AllLinks || f.policy == ContentLinks || n.Type != html.ElementNode {
+		return true
+	}
+
+	if n.Data == "h2" {
+		f.inSection = true
+		f.skipSection = slices.Contains(boilerplateSectionIds, nodeAttr(n, "id"))
+	}
+	if f.skipSection || (f.policy == LeadLinks && f.inSection) {
+		return false
+	}
+	for _, class := range strings.Fields(nodeAttr(n, "class")) {
+		if slices.Contains(boilerplateClasses, class) {
+			return false
+		}
+	}
+	return true
+}
+
+func nodeAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package wikiSteps
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func articleDirLinkSource(t *testing.T) *HtmlDirLinkSource {
+	content, err := os.ReadFile(filepath.Join("testdata", "article.html"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	dir := t.TempDir()
+	if err = os.WriteFile(filepath.Join(dir, "Machine_translation.html"), content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return NewHtmlDirLinkSource(nopLogger{}, testSite, dir)
+}
+
+func TestExtractionPolicies(t *testing.T) {
+	source := articleDirLinkSource(t)
+	tests := map[ExtractionPolicy][]string{
+		AllLinks: {
+			"Al-Kindi", "Artificial_intelligence", "Association_for_Computational_Linguistics", "Comparison_of_machine_translation_applications",
+			"Computational_linguistics", "Computer_science", "Footer_article", "Machine_Translation_(journal)", "Natural_language",
+			"Natural_language_processing", "Sidebar_article", "Warren_Weaver",
+		},
+		ContentLinks: {
+			"Al-Kindi", "Artificial_intelligence", "Association_for_Computational_Linguistics", "Comparison_of_machine_translation_applications",
+			"Computational_linguistics", "Computer_science", "Machine_Translation_(journal)", "Natural_language",
+			"Natural_language_processing", "Warren_Weaver",
+		},
+		ArticleLinks: {"Al-Kindi", "Computational_linguistics", "Natural_language", "Warren_Weaver"},
+		LeadLinks:    {"Computational_linguistics", "Natural_language"},
+	}
+
+	for policy, titles := range tests {
+		links, err := source.PolicyLinks(context.Background(), "test", page("Machine_translation"), policy)
+		slices.Sort(links)
+		expected := make([]string, len(titles))
+		for i, title := range titles {
+			expected[i] = page(title)
+		}
+		if err != nil || !slices.Equal(links, expected) {
+			t.Errorf("Policy: %s Expected: %v Actual: %v (%v)", policy, expected, links, err)
+		}
+	}
+}
+
+func TestFindValidPathsExtractionPolicy(t *testing.T) {
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, articleDirLinkSource(t), 5, 10*time.Second, 4)
+
+	result, err := service.FindValidPaths(context.Background(), page("Machine_translation"), page("Warren_Weaver"), 1, SearchOptions{Strategy: ShortestStrategy, Extraction: ArticleLinks})
+	if expected := 1; err != nil || len(result.Paths) != expected {
+		t.Errorf("Expected: %d paths Actual: %v (%v)", expected, result.Paths, err)
+	}
+
+	result, err = service.FindValidPaths(context.Background(), page("Machine_translation"), page("Warren_Weaver"), 1, SearchOptions{Strategy: ShortestStrategy, Extraction: LeadLinks})
+	if err != nil || len(result.Paths) != 0 {
+		t.Errorf("Expected: no paths Actual: %v (%v)", result.Paths, err)
+	}
+
+	if err = service.ValidateSearch(page("Machine_translation"), page("Warren_Weaver"), 1, SearchOptions{Strategy: BidirectionalStrategy, Extraction: LeadLinks}); err == nil {
+		t.Errorf("Expected an error for the bidirectional strategy with an extraction policy")
+	}
+
+	memoryService := NewWikistepsServiceWithSource(nopLogger{}, testSite, testLinkSource(), 5, 10*time.Second, 4)
+	if err = memoryService.ValidateSearch(page("A"), page("D"), 3, SearchOptions{Strategy: ShortestStrategy, Extraction: ContentLinks}); err == nil {
+		t.Errorf("Expected an error for a link source without extraction policies")
+	}
+}
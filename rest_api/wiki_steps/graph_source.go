@@ -0,0 +1,217 @@
+package wikiSteps
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// GraphLinkSource serves links from a link graph written by ImportPagelinksDump, the whole
+// graph is held in memory so searches need no network access
+type GraphLinkSource struct {
+	site          WikiSite
+	titles        []string
+	index         map[string]uint32
+	offsets       []uint32 // links of page i are targets[offsets[i]:offsets[i+1]]
+	targets       []uint32
+	redirects     map[uint32]uint32   // redirect page -> page it redirects to
+	redirectsTo   map[uint32][]uint32 // page -> redirect pages pointing to it
+	backlinksOnce sync.Once
+	backOffsets   []uint32
+	backTargets   []uint32
+}
+
+func NewGraphLinkSource(site WikiSite, path string) (*GraphLinkSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error when opening link graph %s; %w", path, err)
+	}
+	defer file.Close()
+
+	graph, err := ReadGraphLinkSource(site, file)
+	if err != nil {
+		return nil, fmt.Errorf("error when reading link graph %s; %w", path, err)
+	}
+	return graph, nil
+}
+
+func ReadGraphLinkSource(site WikiSite, r io.Reader) (*GraphLinkSource, error) {
+	br := bufio.NewReaderSize(r, 1<<20)
+	magic := make([]byte, len(graphFileMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || (string(magic) != graphFileMagic && string(magic) != graphFileMagicV1) {
+		return nil, fmt.Errorf("not a link graph file")
+	}
+
+	numPages, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("error when reading the page count; %w", err)
+	}
+	g := &GraphLinkSource{
+		site:        site,
+		titles:      make([]string, numPages),
+		index:       make(map[string]uint32, numPages),
+		offsets:     make([]uint32, numPages+1),
+		targets:     make([]uint32, 0, numPages),
+		redirects:   make(map[uint32]uint32),
+		redirectsTo: make(map[uint32][]uint32),
+	}
+
+	for i := range g.titles {
+		length, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("error when reading title %d; %w", i, err)
+		}
+		title := make([]byte, length)
+		if _, err = io.ReadFull(br, title); err != nil {
+			return nil, fmt.Errorf("error when reading title %d; %w", i, err)
+		}
+		g.titles[i] = string(title)
+		g.index[g.titles[i]] = uint32(i)
+	}
+
+	for i := range numPages {
+		count, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("error when reading the links of %s; %w", g.titles[i], err)
+		}
+		previous := uint64(0)
+		for range count {
+			delta, err := binary.ReadUvarint(br)
+			if err != nil {
+				return nil, fmt.Errorf("error when reading the links of %s; %w", g.titles[i], err)
+			}
+			previous += delta
+			if previous >= numPages {
+				return nil, fmt.Errorf("link of %s points to unknown page %d", g.titles[i], previous)
+			}
+			g.targets = append(g.targets, uint32(previous))
+		}
+		g.offsets[i+1] = uint32(len(g.targets))
+	}
+	if string(magic) == graphFileMagicV1 {
+		return g, nil
+	}
+
+	numRedirects, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("error when reading the redirect count; %w", err)
+	}
+	for range numRedirects {
+		from, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("error when reading a redirect; %w", err)
+		}
+		to, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("error when reading a redirect; %w", err)
+		}
+		if from >= numPages || to >= numPages {
+			return nil, fmt.Errorf("redirect from page %d to page %d points to an unknown page", from, to)
+		}
+		g.redirects[uint32(from)] = uint32(to)
+		g.redirectsTo[uint32(to)] = append(g.redirectsTo[uint32(to)], uint32(from))
+	}
+	return g, nil
+}
+
+func (g *GraphLinkSource) Links(ctx context.Context, workerName string, url string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i, ok := g.pageIndex(url)
+	if !ok {
+		return []string{}, nil
+	}
+	return g.pageUrls(g.targets[g.offsets[i]:g.offsets[i+1]]), nil
+}
+
+// Backlinks inverts the graph the first time it is called
+func (g *GraphLinkSource) Backlinks(ctx context.Context, workerName string, url string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	g.backlinksOnce.Do(g.indexBacklinks)
+	i, ok := g.pageIndex(url)
+	if !ok {
+		return []string{}, nil
+	}
+	return g.pageUrls(g.backTargets[g.backOffsets[i]:g.backOffsets[i+1]]), nil
+}
+
+// Resolve returns the page url redirects to, links in the graph never point to redirects
+func (g *GraphLinkSource) Resolve(ctx context.Context, workerName string, url string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return url, err
+	}
+	if i, ok := g.pageIndex(url); ok {
+		if to, ok := g.redirects[i]; ok {
+			return g.site.PageUrl(g.titles[to]), nil
+		}
+	}
+	return url, nil
+}
+
+func (g *GraphLinkSource) ResolveAll(ctx context.Context, workerName string, urls []string) (map[string]string, error) {
+	resolved := make(map[string]string)
+	for _, url := range urls {
+		page, err := g.Resolve(ctx, workerName, url)
+		if err != nil {
+			return nil, err
+		}
+		if page != url {
+			resolved[url] = page
+		}
+	}
+	return resolved, nil
+}
+
+func (g *GraphLinkSource) Redirects(ctx context.Context, workerName string, url string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i, ok := g.pageIndex(url)
+	if !ok {
+		return []string{}, nil
+	}
+	return g.pageUrls(g.redirectsTo[i]), nil
+}
+
+func (g *GraphLinkSource) indexBacklinks() {
+	g.backOffsets = make([]uint32, len(g.titles)+1)
+	for _, to := range g.targets {
+		g.backOffsets[to+1] += 1
+	}
+	for i := 1; i < len(g.backOffsets); i += 1 {
+		g.backOffsets[i] += g.backOffsets[i-1]
+	}
+
+	g.backTargets = make([]uint32, len(g.targets))
+	next := append([]uint32{}, g.backOffsets[:len(g.titles)]...)
+	for from := range g.titles {
+		for _, to := range g.targets[g.offsets[from]:g.offsets[from+1]] {
+			g.backTargets[next[to]] = uint32(from)
+			next[to] += 1
+		}
+	}
+}
+
+// pageIndex looks up the page of an article URL, dump titles use underscores instead of spaces
+func (g *GraphLinkSource) pageIndex(url string) (uint32, bool) {
+	i, ok := g.index[strings.ReplaceAll(g.site.PageTitle(url), " ", "_")]
+	return i, ok
+}
+
+func (g *GraphLinkSource) pageUrls(pages []uint32) []string {
+	urls := make([]string, 0, len(pages))
+	for _, p := range pages {
+		if u := g.site.PageUrl(g.titles[p]); g.site.isValidWikiStepUrl(u) {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
@@ -0,0 +1,165 @@
+package wikiSteps
+
+import (
+	"app/rest_api/logging"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// HttpLinkSource fetches pages from live Wikipedia and scrapes their HTML for links, backlinks
+// and redirects are listed through the API of the site, which pages through every backlink
+type HttpLinkSource struct {
+	log    logging.Logger
+	site   WikiSite
+	client *wikiClient
+	api    *ApiLinkSource // shares the client, so both respect the same politeness policy
+}
+
+func NewHttpLinkSource(log logging.Logger, site WikiSite, policy PolitenessPolicy) *HttpLinkSource {
+	client := newWikiClient(log, policy)
+	return &HttpLinkSource{
+		log,
+		site,
+		client,
+		&ApiLinkSource{log, site, client},
+	}
+}
+
+// Probe requests the front page of the site
+func (h *HttpLinkSource) Probe(ctx context.Context) error {
+	return h.client.probe(ctx, h.site.Host+"/")
+}
+
+func (h *HttpLinkSource) Links(ctx context.Context, workerName string, url string) ([]string, error) {
+	return h.fetchLinks(ctx, workerName, url, "", AllLinks)
+}
+
+func (h *HttpLinkSource) PolicyLinks(ctx context.Context, workerName string, url string, policy ExtractionPolicy) ([]string, error) {
+	return h.fetchLinks(ctx, workerName, url, "", policy)
+}
+
+// Backlinks are listed through the API, Special:WhatLinksHere cuts its lists off at 500 pages
+func (h *HttpLinkSource) Backlinks(ctx context.Context, workerName string, url string) ([]string, error) {
+	return h.api.Backlinks(ctx, workerName, url)
+}
+
+// Resolve follows redirects through the API of the site, so the page is not downloaded twice
+func (h *HttpLinkSource) Resolve(ctx context.Context, workerName string, url string) (string, error) {
+	return h.api.Resolve(ctx, workerName, url)
+}
+
+func (h *HttpLinkSource) ResolveAll(ctx context.Context, workerName string, urls []string) (map[string]string, error) {
+	return h.api.ResolveAll(ctx, workerName, urls)
+}
+
+func (h *HttpLinkSource) Redirects(ctx context.Context, workerName string, url string) ([]string, error) {
+	return h.api.Redirects(ctx, workerName, url)
+}
+
+func (h *HttpLinkSource) fetchLinks(ctx context.Context, workerName string, url string, scopeId string, policy ExtractionPolicy) ([]string, error) {
+	fetchStart := time.Now()
+	resp, err := h.client.callWikipedia(ctx, workerName, url)
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		h.log.Debug("Worker found no article at the URL, it has no links", "worker", workerName, "url", url)
+		return []string{}, nil // deleted articles are still linked from pages not purged yet
+	}
+	if err != nil {
+		return nil, fmt.Errorf("worker %s encountered an error when calling Wikipedia; %w", workerName, err)
+	}
+	defer resp.Close()
+
+	// parsing the resposne body and extracting any valid URLs
+	h.log.Debug("Worker is extracting URLs from the response body...", "worker", workerName, "url", url)
+	urls, err := extractWikiLinks(h.log, h.site, resp, workerName, scopeId, policy)
+	if err != nil {
+		return nil, fmt.Errorf("worker %s encountered an error when extracting URLs from the response body for URL %s; %w", workerName, url, err)
+	}
+	observeFetch(fetchStart)
+	return urls, nil
+}
+
+// extractWikiLinks only collects links below the element with the given id that are selected
+// by the policy, an empty id collects links from the whole document
+func extractWikiLinks(log logging.Logger, site WikiSite, body io.Reader, workerName string, scopeId string, policy ExtractionPolicy) ([]string, error) {
+	log = log.With("worker", workerName)
+	log.Trace("Worker is parsing response body to html node...")
+	root, err := html.Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("error when parsing html response body; %w", err)
+	}
+
+	if scopeId != "" {
+		if root = findElementById(root, scopeId); root == nil {
+			log.Trace("Worker found no element with the scope id in response body", "scopeId", scopeId)
+			return []string{}, nil
+		}
+	}
+
+	filter := linkFilter{policy: policy}
+	if root = filter.scope(root); root == nil {
+		log.Trace("Worker found no main content in response body")
+		return []string{}, nil
+	}
+
+	urlSet := make(map[string]struct{}) // set to keep only unique URLs in the path
+
+	var traverse func(n *html.Node) // defining function to traverse nodes
+	traverse = func(n *html.Node) {
+		if n == nil || !filter.visit(n) {
+			return
+		}
+
+		// checking if node is element <a> and has a valid Wikipedia URI
+		if n.Type == html.ElementNode && n.Data == "a" {
+			for _, a := range n.Attr {
+				if a.Key == "href" && site.isValidWikistepUri(a.Val) {
+					url := site.Host + a.Val
+					if _, exists := urlSet[url]; exists {
+						log.Trace("Worker's node has a valid duplicate URL", "url", url, "unique", len(urlSet))
+					} else {
+						urlSet[url] = struct{}{}
+						log.Trace("Worker's node has a valid new URL", "url", url, "unique", len(urlSet))
+					}
+				}
+			}
+		}
+
+		// recursive call to traverse children
+		for c := range n.ChildNodes() {
+			traverse(c)
+		}
+	}
+
+	traverse(root) // call the traverse fucntion on the root node
+
+	urls := make([]string, len(urlSet))
+	i := 0
+	for k, _ := range urlSet {
+		urls[i] = k
+		i += 1
+	}
+	return urls, nil
+}
+
+func findElementById(n *html.Node, id string) *html.Node {
+	if n.Type == html.ElementNode {
+		for _, a := range n.Attr {
+			if a.Key == "id" && a.Val == id {
+				return n
+			}
+		}
+	}
+	for c := range n.ChildNodes() {
+		if found := findElementById(c, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package wikiSteps
+
+import (
+	"app/rest_api/logging"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const searchJobRetention time.Duration = time.Hour // how long finished jobs can still be polled
+
+var ErrShuttingDown = errors.New("search jobs are shutting down")
+
+type SearchJobStatus string
+
+const (
+	SearchJobQueued    SearchJobStatus = "queued"
+	SearchJobRunning   SearchJobStatus = "running"
+	SearchJobCompleted SearchJobStatus = "completed"
+	SearchJobFailed    SearchJobStatus = "failed"
+	SearchJobCancelled SearchJobStatus = "cancelled"
+)
+
+type SearchJob struct {
+	Id       string
+	Start    string
+	Target   string
+	Steps    int
+	Options  SearchOptions
+	monitor  *SearchMonitor
+	mu       sync.Mutex
+	status   SearchJobStatus
+	err      error
+	minimal  bool
+	created  time.Time
+	finished time.Time
+}
+
+// SearchJobSnapshot is the pollable state of a search job
+type SearchJobSnapshot struct {
+	Id         string          `json:"id"`
+	Start      string          `json:"start"`
+	Target     string          `json:"target"`
+	Steps      int             `json:"steps"`
+	Strategy   string          `json:"strategy"`
+	MaxResults int             `json:"maxResults"`
+	Extraction string          `json:"extraction"`
+	Timeout    string          `json:"timeout,omitempty"`
+	Workers    int             `json:"workers,omitempty"`
+	Exclude    []string        `json:"exclude,omitempty"`
+	Through    []string        `json:"through,omitempty"`
+	Categories []string        `json:"categories,omitempty"`
+	Status     SearchJobStatus `json:"status"`
+	Minimal    bool            `json:"minimal"`
+	Error      string          `json:"error,omitempty"`
+	Created    time.Time       `json:"created"`
+	Progress   SearchProgress  `json:"progress"`
+}
+
+// SearchJobs runs searches in the background, with at most maxRunning searches running at once
+type SearchJobs struct {
+	log     logging.Logger
+	service *WikiSteps
+	slots   chan struct{}
+	mu      sync.Mutex
+	jobs    map[string]*SearchJob
+	running sync.WaitGroup // jobs that did not finish yet
+	closed  bool
+}
+
+func NewSearchJobs(log logging.Logger, service *WikiSteps, maxRunning int) *SearchJobs {
+	return &SearchJobs{
+		log:     log,
+		service: service,
+		slots:   make(chan struct{}, maxRunning),
+		jobs:    make(map[string]*SearchJob),
+	}
+}
+
+func (s *SearchJobs) Submit(start string, target string, steps int, options SearchOptions) (*SearchJob, error) {
+	if err := s.service.ValidateSearch(start, target, steps, options); err != nil {
+		return nil, err
+	}
+
+	job := &SearchJob{
+		Id:      uuid.NewString(),
+		Start:   start,
+		Target:  target,
+		Steps:   steps,
+		Options: options,
+		monitor: NewSearchMonitor(),
+		status:  SearchJobQueued,
+		created: time.Now(),
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, ErrShuttingDown
+	}
+	s.removeExpired()
+	s.jobs[job.Id] = job
+	s.running.Add(1)
+	s.mu.Unlock()
+
+	s.log.Info("Search job was queued", "job", job.Id)
+	go s.run(job)
+	return job, nil
+}
+
+func (s *SearchJobs) Get(id string) (*SearchJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	return job, ok
+}
+
+func (s *SearchJobs) Cancel(id string) (*SearchJob, bool) {
+	job, ok := s.Get(id)
+	if ok {
+		s.log.Info("Search job is being cancelled", "job", id)
+		job.monitor.Cancel()
+	}
+	return job, ok
+}
+
+// Shutdown cancels every unfinished job and waits for them to finish or ctx to be done,
+// cancelled jobs keep the progress they made. Jobs submitted afterwards are rejected.
+func (s *SearchJobs) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	for _, job := range s.jobs {
+		job.monitor.Cancel()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("search jobs did not finish before shutdown; %w", ctx.Err())
+	}
+}
+
+func (s *SearchJobs) run(job *SearchJob) {
+	defer s.running.Done()
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-job.monitor.Cancelled():
+		job.finish(SearchJobCancelled, SearchResult{}, nil)
+		return
+	}
+
+	job.setStatus(SearchJobRunning)
+	s.log.Info("Search job is running", "job", job.Id)
+	result, err := s.service.FindValidPathsWithMonitor(context.Background(), job.Start, job.Target, job.Steps, job.Options, job.monitor)
+
+	switch {
+	case job.monitor.IsCancelled() || errors.Is(err, ErrCancelled):
+		job.finish(SearchJobCancelled, result, nil)
+	case err != nil:
+		s.log.Error("Search job failed", "job", job.Id, "error", err)
+		job.finish(SearchJobFailed, result, err)
+	default:
+		job.finish(SearchJobCompleted, result, nil)
+	}
+	s.log.Info("Search job finished", "job", job.Id)
+}
+
+// removeExpired must be called with s.mu held
+func (s *SearchJobs) removeExpired() {
+	for id, job := range s.jobs {
+		job.mu.Lock()
+		expired := !job.finished.IsZero() && time.Since(job.finished) > searchJobRetention
+		job.mu.Unlock()
+		if expired {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+func (j *SearchJob) Snapshot() SearchJobSnapshot {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	snapshot := SearchJobSnapshot{
+		Id:         j.Id,
+		Start:      j.Start,
+		Target:     j.Target,
+		Steps:      j.Steps,
+		Strategy:   j.Options.Strategy.String(),
+		MaxResults: j.Options.MaxResults,
+		Extraction: j.Options.Extraction.String(),
+		Workers:    j.Options.NumWorkers,
+		Exclude:    j.Options.Exclude,
+		Through:    j.Options.Through,
+		Categories: j.Options.Categories,
+		Status:     j.status,
+		Minimal:    j.minimal,
+		Created:    j.created,
+		Progress:   j.monitor.Progress(),
+	}
+	if j.Options.Timeout > 0 {
+		snapshot.Timeout = j.Options.Timeout.String()
+	}
+	if j.err != nil {
+		snapshot.Error = j.err.Error()
+	}
+	return snapshot
+}
+
+func (j *SearchJob) setStatus(status SearchJobStatus) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status = status
+}
+
+func (j *SearchJob) finish(status SearchJobStatus, result SearchResult, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status = status
+	j.minimal = result.Minimal
+	j.err = err
+	j.finished = time.Now()
+}
@@ -0,0 +1,103 @@
+package wikiSteps
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func waitForSearchJob(t *testing.T, job *SearchJob) SearchJobSnapshot {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if snapshot := job.Snapshot(); snapshot.Status != SearchJobQueued && snapshot.Status != SearchJobRunning {
+			return snapshot
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Search job %s did not finish", job.Id)
+	return SearchJobSnapshot{}
+}
+
+func TestSearchJobs(t *testing.T) {
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, testLinkSource(), 5, 10*time.Second, 4)
+	jobs := NewSearchJobs(nopLogger{}, service, 1)
+
+	if _, err := jobs.Submit(page("A"), page("D"), 10, SearchOptions{Strategy: BidirectionalStrategy}); err == nil {
+		t.Errorf("Expected an error for steps above the maximum")
+	}
+
+	job, err := jobs.Submit(page("A"), page("D"), 3, SearchOptions{Strategy: BidirectionalStrategy})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if found, ok := jobs.Get(job.Id); !ok || found != job {
+		t.Fatalf("Expected to find search job %s", job.Id)
+	}
+
+	snapshot := waitForSearchJob(t, job)
+	if snapshot.Status != SearchJobCompleted || len(snapshot.Progress.Paths) != 1 {
+		t.Errorf("Expected: completed with 1 path Actual: %s with %v", snapshot.Status, snapshot.Progress.Paths)
+	}
+}
+
+func TestSearchJobsCancel(t *testing.T) {
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, testLinkSource(), 5, 10*time.Second, 1)
+	jobs := NewSearchJobs(nopLogger{}, service, 1)
+
+	job, err := jobs.Submit(page("A"), page("Missing"), 3, SearchOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	jobs.Cancel(job.Id)
+
+	if snapshot := waitForSearchJob(t, job); snapshot.Status != SearchJobCancelled {
+		t.Errorf("Expected: %s Actual: %s", SearchJobCancelled, snapshot.Status)
+	}
+}
+
+func TestSearchJobsCancelWhileResolving(t *testing.T) {
+	resolver := newBlockingResolver()
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, resolver, 5, 10*time.Second, 1)
+	jobs := NewSearchJobs(nopLogger{}, service, 1)
+
+	job, err := jobs.Submit(page("A"), page("D"), 3, SearchOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	<-resolver.resolving
+	jobs.Cancel(job.Id)
+
+	if snapshot := waitForSearchJob(t, job); snapshot.Status != SearchJobCancelled || snapshot.Error != "" {
+		t.Errorf("Expected: %s Actual: %s (%s)", SearchJobCancelled, snapshot.Status, snapshot.Error)
+	}
+}
+
+func TestSearchJobsShutdown(t *testing.T) {
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, testLinkSource(), 5, 10*time.Second, 1)
+	jobs := NewSearchJobs(nopLogger{}, service, 1)
+
+	running, err := jobs.Submit(page("A"), page("Missing"), 3, SearchOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	queued, err := jobs.Submit(page("A"), page("Missing"), 3, SearchOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err = jobs.Shutdown(ctx); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, job := range []*SearchJob{running, queued} {
+		if status := job.Snapshot().Status; status != SearchJobCancelled {
+			t.Errorf("Expected: %s Actual: %s", SearchJobCancelled, status)
+		}
+	}
+
+	if _, err = jobs.Submit(page("A"), page("D"), 3, SearchOptions{}); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("Expected: %v Actual: %v", ErrShuttingDown, err)
+	}
+}
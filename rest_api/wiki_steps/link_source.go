@@ -0,0 +1,223 @@
+package wikiSteps
+
+import (
+	"app/rest_api/logging"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// LinkSource provides the outgoing links of a page and the pages linking back to it.
+// Both methods should stop and return the context's error once ctx is done.
+type LinkSource interface {
+	Links(ctx context.Context, workerName string, url string) ([]string, error)
+	Backlinks(ctx context.Context, workerName string, url string) ([]string, error)
+}
+
+// UpstreamProber is implemented by link sources that read their links from an upstream such
+// as a wiki, Probe returns an error when the upstream cannot be reached
+type UpstreamProber interface {
+	Probe(ctx context.Context) error
+}
+
+// findSource returns the innermost of the link sources wrapped by source, such as the source of a
+// PersistentLinkSource, as a T. Wrappers only pass the optional interfaces through to the source
+// they wrap, so they are looked through even when they implement T themselves.
+func findSource[T any](source LinkSource) (T, bool) {
+	for {
+		wrapper, ok := source.(interface{ Unwrap() LinkSource })
+		if !ok {
+			break
+		}
+		source = wrapper.Unwrap()
+	}
+	found, ok := source.(T)
+	return found, ok
+}
+
+// MemoryLinkSource serves links from a map of page URL to outgoing link URLs
+type MemoryLinkSource struct {
+	links      map[string][]string
+	backlinks  map[string][]string
+	redirects  map[string]string
+	categories map[string][]string
+}
+
+func NewMemoryLinkSource(pages map[string][]string) *MemoryLinkSource {
+	return NewMemoryLinkSourceWithRedirects(pages, nil)
+}
+
+// NewMemoryLinkSourceWithRedirects also serves redirects from a map of redirect URL to target URL
+func NewMemoryLinkSourceWithRedirects(pages map[string][]string, redirects map[string]string) *MemoryLinkSource {
+	links := make(map[string][]string, len(pages))
+	backlinks := make(map[string][]string)
+	for page, urls := range pages {
+		links[page] = append([]string{}, urls...)
+		for _, url := range urls {
+			backlinks[url] = append(backlinks[url], page)
+		}
+	}
+	return &MemoryLinkSource{
+		links,
+		backlinks,
+		redirects,
+		make(map[string][]string),
+	}
+}
+
+// AddCategory adds the pages to the category, it must be called before the source is searched
+func (m *MemoryLinkSource) AddCategory(category string, pages ...string) {
+	m.categories[category] = append(m.categories[category], pages...)
+}
+
+func (m *MemoryLinkSource) Links(ctx context.Context, workerName string, url string) ([]string, error) {
+	return append([]string{}, m.links[url]...), nil
+}
+
+func (m *MemoryLinkSource) Backlinks(ctx context.Context, workerName string, url string) ([]string, error) {
+	return append([]string{}, m.backlinks[url]...), nil
+}
+
+func (m *MemoryLinkSource) CategoryMembers(ctx context.Context, workerName string, category string) ([]string, error) {
+	return append([]string{}, m.categories[category]...), nil
+}
+
+func (m *MemoryLinkSource) Resolve(ctx context.Context, workerName string, url string) (string, error) {
+	if target, ok := m.redirects[url]; ok {
+		return target, nil
+	}
+	return url, nil
+}
+
+func (m *MemoryLinkSource) ResolveAll(ctx context.Context, workerName string, urls []string) (map[string]string, error) {
+	resolved := make(map[string]string)
+	for _, url := range urls {
+		if target, ok := m.redirects[url]; ok {
+			resolved[url] = target
+		}
+	}
+	return resolved, nil
+}
+
+func (m *MemoryLinkSource) Redirects(ctx context.Context, workerName string, url string) ([]string, error) {
+	redirects := make([]string, 0)
+	for redirect, target := range m.redirects {
+		if target == url {
+			redirects = append(redirects, redirect)
+		}
+	}
+	return redirects, nil
+}
+
+// HtmlDirLinkSource serves links from a directory of saved article pages named <title>.html,
+// with any '/' in the title written as %2F
+type HtmlDirLinkSource struct {
+	log           logging.Logger
+	site          WikiSite
+	dir           string
+	backlinksOnce sync.Once
+	backlinks     map[string][]string
+	backlinksErr  error
+}
+
+func NewHtmlDirLinkSource(log logging.Logger, site WikiSite, dir string) *HtmlDirLinkSource {
+	return &HtmlDirLinkSource{
+		log:  log,
+		site: site,
+		dir:  dir,
+	}
+}
+
+// Probe checks that the directory of saved pages can be read
+func (d *HtmlDirLinkSource) Probe(ctx context.Context) error {
+	if _, err := os.ReadDir(d.dir); err != nil {
+		return fmt.Errorf("error when reading saved page directory %s; %w", d.dir, err)
+	}
+	return nil
+}
+
+func (d *HtmlDirLinkSource) Links(ctx context.Context, workerName string, url string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return d.readLinks(workerName, url, AllLinks)
+}
+
+func (d *HtmlDirLinkSource) PolicyLinks(ctx context.Context, workerName string, url string, policy ExtractionPolicy) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return d.readLinks(workerName, url, policy)
+}
+
+func (d *HtmlDirLinkSource) readLinks(workerName string, url string, policy ExtractionPolicy) ([]string, error) {
+	path := filepath.Join(d.dir, d.pageFileName(url))
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		d.log.Debug("Worker found no saved page", "worker", workerName, "url", url)
+		return []string{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("worker %s encountered an error when opening %s; %w", workerName, path, err)
+	}
+	defer file.Close()
+
+	d.log.Debug("Worker is extracting URLs from saved page...", "worker", workerName, "url", url, "path", path)
+	urls, err := extractWikiLinks(d.log, d.site, file, workerName, "", policy)
+	if err != nil {
+		return nil, fmt.Errorf("worker %s encountered an error when extracting URLs from saved page %s; %w", workerName, path, err)
+	}
+	return urls, nil
+}
+
+// Backlinks reads every saved page the first time it is called to build the reverse index
+func (d *HtmlDirLinkSource) Backlinks(ctx context.Context, workerName string, url string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.backlinksOnce.Do(func() {
+		d.backlinks, d.backlinksErr = d.indexBacklinks(workerName)
+	})
+	if d.backlinksErr != nil {
+		return nil, d.backlinksErr
+	}
+	return append([]string{}, d.backlinks[url]...), nil
+}
+
+func (d *HtmlDirLinkSource) indexBacklinks(workerName string) (map[string][]string, error) {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil, fmt.Errorf("error when reading saved page directory %s; %w", d.dir, err)
+	}
+
+	d.log.Debug("Worker is indexing backlinks of saved pages...", "worker", workerName, "pages", len(entries))
+	backlinks := make(map[string][]string)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".html") {
+			continue
+		}
+		page := d.pageFileUrl(e.Name())
+		urls, err := d.readLinks(workerName, page, AllLinks)
+		if err != nil {
+			return nil, err
+		}
+		for _, url := range urls {
+			backlinks[url] = append(backlinks[url], page)
+		}
+	}
+	return backlinks, nil
+}
+
+func (d *HtmlDirLinkSource) pageFileName(url string) string {
+	title := strings.TrimPrefix(url, d.site.Host+d.site.ArticlePath)
+	return strings.ReplaceAll(title, "/", "%2F") + ".html"
+}
+
+func (d *HtmlDirLinkSource) pageFileUrl(name string) string {
+	title := strings.ReplaceAll(strings.TrimSuffix(name, ".html"), "%2F", "/")
+	return d.site.Host + d.site.ArticlePath + title
+}
@@ -0,0 +1,38 @@
+package wikiSteps
+
+import (
+	"app/rest_api/metrics"
+	"time"
+)
+
+// metrics of all searches, served by the /metrics endpoint
+var (
+	pagesFetched = metrics.DefaultRegistry.NewCounter(
+		"wikisteps_pages_fetched_total", "Pages whose links were fetched from the wiki, cached links are not counted.")
+	fetchDuration = metrics.DefaultRegistry.NewHistogram(
+		"wikisteps_fetch_duration_seconds", "Time taken to fetch the links of a page from the wiki.", metrics.DefaultBuckets)
+	httpResponses = metrics.DefaultRegistry.NewCounterVec(
+		"wikisteps_http_responses_total", "Responses to requests to the wiki by status code, error when no response was received.", "status")
+	semaphoreWait = metrics.DefaultRegistry.NewHistogram(
+		"wikisteps_semaphore_wait_seconds", "Time requests to the wiki waited for the concurrency semaphore.", metrics.DefaultBuckets)
+	jobsQueued = metrics.DefaultRegistry.NewCounter(
+		"wikisteps_jobs_queued_total", "Jobs queued by searches.")
+	jobsInFlight = metrics.DefaultRegistry.NewGauge(
+		"wikisteps_jobs_in_flight", "Jobs being worked on.")
+	jobsCompleted = metrics.DefaultRegistry.NewCounter(
+		"wikisteps_jobs_completed_total", "Jobs completed by searches.")
+	idleWorkers = metrics.DefaultRegistry.NewGauge(
+		"wikisteps_idle_workers", "Workers of forward searches waiting for a job.")
+	searchesStarted = metrics.DefaultRegistry.NewCounterVec(
+		"wikisteps_searches_total", "Searches started by strategy.", "strategy")
+	pathsFound = metrics.DefaultRegistry.NewCounter(
+		"wikisteps_paths_found_total", "Paths found by searches.")
+	searchesTimedOut = metrics.DefaultRegistry.NewCounter(
+		"wikisteps_searches_timed_out_total", "Searches stopped by their timeout.")
+)
+
+// observeFetch records a successful fetch of the links of a page from the wiki that began at fetchStart
+func observeFetch(fetchStart time.Time) {
+	fetchDuration.ObserveDuration(time.Since(fetchStart))
+	pagesFetched.Inc()
+}
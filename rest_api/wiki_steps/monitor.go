@@ -0,0 +1,132 @@
+package wikiSteps
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// SearchMonitor tracks the progress of a single running search and allows it to be cancelled
+// from another goroutine. A monitor must not be reused across searches.
+type SearchMonitor struct {
+	cancelCh      chan struct{}
+	cancelOnce    sync.Once
+	jobsQueued    atomic.Int64
+	jobsCompleted atomic.Int64
+	mu            sync.Mutex
+	depth         int
+	paths         [][]string
+	cache         *linkCache
+	pathCh        chan []string
+	searchDone    <-chan struct{} // closed once the context of the search is done
+}
+
+// SearchProgress is a snapshot of a running search
+type SearchProgress struct {
+	JobsQueued    int64      `json:"jobsQueued"`
+	JobsCompleted int64      `json:"jobsCompleted"`
+	PagesFetched  int64      `json:"pagesFetched"`
+	Depth         int        `json:"depth"`
+	PathsFound    int        `json:"pathsFound"`
+	Paths         [][]string `json:"paths"`
+}
+
+func NewSearchMonitor() *SearchMonitor {
+	return &SearchMonitor{
+		cancelCh: make(chan struct{}),
+		paths:    make([][]string, 0),
+	}
+}
+
+// Cancel stops the search, which returns the paths found so far
+func (m *SearchMonitor) Cancel() {
+	m.cancelOnce.Do(func() { close(m.cancelCh) })
+}
+
+func (m *SearchMonitor) Cancelled() <-chan struct{} {
+	return m.cancelCh
+}
+
+func (m *SearchMonitor) IsCancelled() bool {
+	select {
+	case <-m.cancelCh:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m *SearchMonitor) Progress() SearchProgress {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	progress := SearchProgress{
+		JobsQueued:    m.jobsQueued.Load(),
+		JobsCompleted: m.jobsCompleted.Load(),
+		Depth:         m.depth,
+		PathsFound:    len(m.paths),
+		Paths:         make([][]string, len(m.paths)),
+	}
+	for i, p := range m.paths {
+		progress.Paths[i] = slices.Clone(p)
+	}
+	if m.cache != nil {
+		progress.PagesFetched = m.cache.Misses()
+	}
+	return progress
+}
+
+// StreamPaths returns a channel receiving every path as soon as it is found. It must be called
+// before the search starts and the channel must be read until the search returns or is cancelled.
+func (m *SearchMonitor) StreamPaths() <-chan []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.pathCh == nil {
+		m.pathCh = make(chan []string, 100)
+	}
+	return m.pathCh
+}
+
+func (m *SearchMonitor) start(ctx context.Context, cache *linkCache) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cache = cache
+	m.searchDone = ctx.Done()
+}
+
+func (m *SearchMonitor) jobQueued(depth int) {
+	jobsQueued.Inc()
+	m.jobsQueued.Add(1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.depth = max(m.depth, depth)
+}
+
+func (m *SearchMonitor) jobCompleted() {
+	jobsCompleted.Inc()
+	m.jobsCompleted.Add(1)
+}
+
+func (m *SearchMonitor) pathFound(path []string) {
+	pathsFound.Inc()
+	m.mu.Lock()
+	m.paths = append(m.paths, slices.Clone(path))
+	pathCh, searchDone := m.pathCh, m.searchDone
+	m.mu.Unlock()
+
+	if pathCh == nil {
+		return
+	}
+	// paths are still streamed after the search is done as long as the reader keeps up,
+	// a stalled reader only blocks the search until it is cancelled or its context is done
+	select {
+	case pathCh <- slices.Clone(path):
+	default:
+		select {
+		case pathCh <- slices.Clone(path):
+		case <-m.cancelCh:
+		case <-searchDone:
+		}
+	}
+}
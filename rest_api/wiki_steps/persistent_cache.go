@@ -0,0 +1,255 @@
+package wikiSteps
+
+import (
+	"app/rest_api/logging"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const persistentLinkCacheFile string = "links.db"
+
+var (
+	linksBucket     []byte   = []byte("links")
+	backlinksBucket []byte   = []byte("backlinks")
+	resolvedBucket  []byte   = []byte("resolved") // single entry lists of the page a redirect resolves to
+	redirectsBucket []byte   = []byte("redirects")
+	linkBuckets     [][]byte = [][]byte{linksBucket, backlinksBucket, resolvedBucket, redirectsBucket}
+)
+
+type persistentLinkEntry struct {
+	FetchedAt time.Time `json:"fetchedAt"`
+	Links     []string  `json:"links"`
+}
+
+// PersistentLinkSource stores the links fetched from another LinkSource in a bolt file,
+// so that pages fetched within the TTL are served from disk even after a restart
+type PersistentLinkSource struct {
+	log    logging.Logger
+	source LinkSource
+	db     *bolt.DB
+	ttl    time.Duration
+}
+
+func NewPersistentLinkSource(log logging.Logger, source LinkSource, dir string, ttl time.Duration) (*PersistentLinkSource, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error when creating link cache directory %s; %w", dir, err)
+	}
+
+	path := filepath.Join(dir, persistentLinkCacheFile)
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("error when opening link cache %s; %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range linkBuckets {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error when creating link cache buckets; %w", err)
+	}
+
+	return &PersistentLinkSource{
+		log,
+		source,
+		db,
+		ttl,
+	}, nil
+}
+
+func (p *PersistentLinkSource) Links(ctx context.Context, workerName string, url string) ([]string, error) {
+	return p.get(ctx, linksBucket, p.source.Links, workerName, url)
+}
+
+func (p *PersistentLinkSource) Backlinks(ctx context.Context, workerName string, url string) ([]string, error) {
+	return p.get(ctx, backlinksBucket, p.source.Backlinks, workerName, url)
+}
+
+// PolicyLinks caches the links of every policy separately, the wrapped source has to be a PolicyLinkSource
+func (p *PersistentLinkSource) PolicyLinks(ctx context.Context, workerName string, url string, policy ExtractionPolicy) ([]string, error) {
+	if policy == AllLinks {
+		return p.Links(ctx, workerName, url)
+	}
+	source, ok := p.source.(PolicyLinkSource)
+	if !ok {
+		return nil, fmt.Errorf("link source does not support extraction policy %s", policy)
+	}
+	// the policy is part of the key, so the fetch is bound to the URL instead of reading it from the key
+	return p.get(ctx, linksBucket, func(ctx context.Context, workerName string, _ string) ([]string, error) {
+		return source.PolicyLinks(ctx, workerName, url, policy)
+	}, workerName, policy.String()+" "+url)
+}
+
+// Unwrap returns the link source whose links are cached
+func (p *PersistentLinkSource) Unwrap() LinkSource {
+	return p.source
+}
+
+// Resolve serves the redirects of the wrapped source when it is a RedirectResolver
+func (p *PersistentLinkSource) Resolve(ctx context.Context, workerName string, url string) (string, error) {
+	resolver, ok := p.source.(RedirectResolver)
+	if !ok {
+		return url, nil
+	}
+	resolved, err := p.get(ctx, resolvedBucket, func(ctx context.Context, workerName string, url string) ([]string, error) {
+		page, err := resolver.Resolve(ctx, workerName, url)
+		return []string{page}, err
+	}, workerName, url)
+	if err != nil || len(resolved) == 0 {
+		return url, err
+	}
+	return resolved[0], nil
+}
+
+// ResolveAll only asks the wrapped source for the URLs that are not cached, pages that are no
+// redirect are cached as resolving to themselves
+func (p *PersistentLinkSource) ResolveAll(ctx context.Context, workerName string, urls []string) (map[string]string, error) {
+	resolver, ok := p.source.(RedirectResolver)
+	if !ok {
+		return map[string]string{}, nil
+	}
+
+	resolved := make(map[string]string, len(urls))
+	missing := make([]string, 0)
+	for _, url := range urls {
+		if page, found := p.read(resolvedBucket, workerName, url); found && len(page) > 0 {
+			resolved[url] = page[0]
+		} else {
+			missing = append(missing, url)
+		}
+	}
+	if len(missing) == 0 {
+		return resolved, nil
+	}
+
+	fetched, err := resolver.ResolveAll(ctx, workerName, missing)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string][]string, len(missing))
+	for _, url := range missing {
+		resolved[url] = cmp.Or(fetched[url], url)
+		entries[url] = []string{resolved[url]}
+	}
+	return resolved, p.write(resolvedBucket, workerName, entries)
+}
+
+func (p *PersistentLinkSource) Redirects(ctx context.Context, workerName string, url string) ([]string, error) {
+	resolver, ok := p.source.(RedirectResolver)
+	if !ok {
+		return []string{}, nil
+	}
+	return p.get(ctx, redirectsBucket, resolver.Redirects, workerName, url)
+}
+
+// Purge deletes the cached entries and returns how many were deleted, when expiredOnly is set
+// only entries older than the TTL are deleted
+func (p *PersistentLinkSource) Purge(expiredOnly bool) (int, error) {
+	purged := 0
+	err := p.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range linkBuckets {
+			b := tx.Bucket(name)
+			c := b.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				if expiredOnly {
+					var entry persistentLinkEntry
+					if err := json.Unmarshal(v, &entry); err == nil && !p.isExpired(entry) {
+						continue
+					}
+				}
+				if err := c.Delete(); err != nil {
+					return err
+				}
+				purged += 1
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error when purging link cache; %w", err)
+	}
+	p.log.Info("Purged entries from the persistent link cache", "purged", purged)
+	return purged, nil
+}
+
+func (p *PersistentLinkSource) Close() error {
+	return p.db.Close()
+}
+
+func (p *PersistentLinkSource) get(ctx context.Context, bucket []byte, fetch func(context.Context, string, string) ([]string, error), workerName string, url string) ([]string, error) {
+	if links, found := p.read(bucket, workerName, url); found {
+		return links, nil
+	}
+
+	links, err := fetch(ctx, workerName, url)
+	if err != nil || links == nil {
+		return links, err
+	}
+	if err = p.write(bucket, workerName, map[string][]string{url: links}); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// read returns the links of an entry that was not expired yet
+func (p *PersistentLinkSource) read(bucket []byte, workerName string, url string) ([]string, bool) {
+	var entry persistentLinkEntry
+	var found bool
+	err := p.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(bucket).Get([]byte(url))
+		if v == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(v, &entry)
+	})
+	if err != nil {
+		p.log.Error("Worker could not read the persistent link cache entry", "worker", workerName, "url", url, "error", err)
+		return nil, false
+	}
+	if found && !p.isExpired(entry) {
+		p.log.Trace("Worker found the links in the persistent link cache", "worker", workerName, "url", url, "links", len(entry.Links))
+		return entry.Links, true
+	}
+	return nil, false
+}
+
+// write stores the entries in a single transaction, failing to store them is only logged
+func (p *PersistentLinkSource) write(bucket []byte, workerName string, entries map[string][]string) error {
+	values := make(map[string][]byte, len(entries))
+	for url, links := range entries {
+		value, err := json.Marshal(persistentLinkEntry{time.Now(), links})
+		if err != nil {
+			return fmt.Errorf("error when encoding link cache entry for URL %s; %w", url, err)
+		}
+		values[url] = value
+	}
+	err := p.db.Update(func(tx *bolt.Tx) error {
+		for url, value := range values {
+			if err := tx.Bucket(bucket).Put([]byte(url), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		p.log.Error("Worker could not write the persistent link cache entries", "worker", workerName, "entries", len(entries), "error", err)
+	}
+	return nil
+}
+
+func (p *PersistentLinkSource) isExpired(entry persistentLinkEntry) bool {
+	return p.ttl > 0 && time.Since(entry.FetchedAt) > p.ttl
+}
@@ -0,0 +1,107 @@
+package wikiSteps
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPersistentLinkSource(t *testing.T) {
+	dir := t.TempDir()
+	source := &countingLinkSource{LinkSource: testLinkSource()}
+
+	cache, err := NewPersistentLinkSource(nopLogger{}, source, dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Links(context.Background(), "test", page("A"))
+	cache.Close()
+
+	// reopening the cache should serve the page from disk
+	cache, err = NewPersistentLinkSource(nopLogger{}, source, dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+
+	links, err := cache.Links(context.Background(), "test", page("A"))
+	if err != nil || len(links) != 2 {
+		t.Errorf("Expected: 2 links Actual: %v (%v)", links, err)
+	}
+	if calls := source.calls.Load(); calls != 1 {
+		t.Errorf("Expected: %d Actual: %d", 1, calls)
+	}
+
+	if purged, err := cache.Purge(true); err != nil || purged != 0 {
+		t.Errorf("Expected: %d expired entries purged Actual: %d (%v)", 0, purged, err)
+	}
+	if purged, err := cache.Purge(false); err != nil || purged != 1 {
+		t.Errorf("Expected: %d entries purged Actual: %d (%v)", 1, purged, err)
+	}
+
+	cache.Links(context.Background(), "test", page("A"))
+	if calls := source.calls.Load(); calls != 2 {
+		t.Errorf("Expected: %d Actual: %d", 2, calls)
+	}
+}
+
+func TestPersistentLinkSourcePolicies(t *testing.T) {
+	cache, err := NewPersistentLinkSource(nopLogger{}, articleDirLinkSource(t), t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+
+	if !supportsExtractionPolicy(cache, LeadLinks) {
+		t.Errorf("Expected the cache to support the extraction policies of the wrapped source")
+	}
+
+	// both policies are served from their own entry
+	for range 2 {
+		lead, err := cache.PolicyLinks(context.Background(), "test", page("Machine_translation"), LeadLinks)
+		if err != nil || len(lead) != 2 {
+			t.Errorf("Expected: 2 links Actual: %v (%v)", lead, err)
+		}
+		links, err := cache.Links(context.Background(), "test", page("Machine_translation"))
+		if err != nil || len(links) != 12 {
+			t.Errorf("Expected: 12 links Actual: %v (%v)", links, err)
+		}
+	}
+}
+
+type countingResolver struct {
+	*MemoryLinkSource
+	resolved [][]string
+}
+
+func (c *countingResolver) ResolveAll(ctx context.Context, workerName string, urls []string) (map[string]string, error) {
+	c.resolved = append(c.resolved, slices.Clone(urls))
+	return c.MemoryLinkSource.ResolveAll(ctx, workerName, urls)
+}
+
+func TestPersistentLinkSourceResolveAll(t *testing.T) {
+	source := &countingResolver{MemoryLinkSource: NewMemoryLinkSourceWithRedirects(nil, map[string]string{page("R"): page("B")})}
+	cache, err := NewPersistentLinkSource(nopLogger{}, source, t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+
+	for range 2 {
+		resolved, err := cache.ResolveAll(context.Background(), "test", []string{page("R"), page("C")})
+		if err != nil || resolved[page("R")] != page("B") || resolved[page("C")] != page("C") {
+			t.Errorf("Expected: R to resolve to B and C to itself Actual: %v (%v)", resolved, err)
+		}
+	}
+	resolved, err := cache.ResolveAll(context.Background(), "test", []string{page("C"), page("D")})
+	if err != nil || resolved[page("D")] != page("D") {
+		t.Errorf("Expected: D to resolve to itself Actual: %v (%v)", resolved, err)
+	}
+
+	// pages that are no redirect are cached as well, only D was unknown to the last batch
+	expected := [][]string{{page("R"), page("C")}, {page("D")}}
+	if !slices.EqualFunc(source.resolved, expected, slices.Equal) {
+		t.Errorf("Expected: %v Actual: %v", expected, source.resolved)
+	}
+}
@@ -3,34 +3,81 @@ package wikiSteps
 import (
 	"app/rest_api/logging"
 	"app/rest_api/util"
+	"context"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"regexp"
 	"strings"
 	"sync"
 	"time"
 	"math"
 
 	"slices"
-
-	"golang.org/x/net/html"
 )
 
+type SearchStrategy int
+
 const (
-	WikipediaDomain string = "https://en.wikipedia.org"
-	WikiPrefix      string = "/wiki/"
+	ForwardStrategy       SearchStrategy = iota // expand only from the start page
+	BidirectionalStrategy                       // expand from the start page and backwards from the target page
+	ShortestStrategy                            // expand from the start page one level at a time, stopping at the first level reaching the target
+	BestFirstStrategy                           // expand the pages most similar to the target page first
 )
 
-var (
-	wikiBlockPrefixList []string       = []string{"/wiki/Main_Page"}
-	reWikiBlockPrefix   *regexp.Regexp = regexp.MustCompile(`^/wiki/\w+:.*`)
-	httpSem             chan struct{}  = make(chan struct{}, 10) // semaphore for limiting the number of requests to wikipedia
-)
+func ParseSearchStrategy(s string) (SearchStrategy, error) {
+	switch strings.ToLower(s) {
+	case "", "forward":
+		return ForwardStrategy, nil
+	case "bidirectional":
+		return BidirectionalStrategy, nil
+	case "shortest":
+		return ShortestStrategy, nil
+	case "bestfirst", "best-first":
+		return BestFirstStrategy, nil
+	default:
+		return ForwardStrategy, fmt.Errorf("unknown search strategy '%s'", s)
+	}
+}
+
+func (s SearchStrategy) String() string {
+	switch s {
+	case ForwardStrategy:
+		return "forward"
+	case BidirectionalStrategy:
+		return "bidirectional"
+	case ShortestStrategy:
+		return "shortest"
+	case BestFirstStrategy:
+		return "bestfirst"
+	default:
+		return fmt.Sprintf("SearchStrategy(%d)", int(s))
+	}
+}
+
+type SearchResult struct {
+	Paths       [][]string
+	Minimal     bool  // every path is guaranteed to be a shortest path from start to target
+	TimedOut    bool  // the search was stopped by its timeout, paths are the ones found until then
+	Cancelled   bool  // the search was cancelled, such as on shutdown, paths are the ones found until then
+	CacheHits   int64 // link lookups served from the search's link cache
+	CacheMisses int64 // link lookups fetched from the link source
+}
+
+// SearchOptions are the options of a single search, the zero value of each option is its default
+type SearchOptions struct {
+	Strategy   SearchStrategy
+	MaxResults int              // stop once this many paths were found, 0 finds every path
+	Timeout    time.Duration    // capped by the service's timeout, 0 uses the service's timeout
+	NumWorkers int              // capped by the service's worker count, 0 uses the service's worker count
+	Extraction ExtractionPolicy // only the links of each page selected by the policy are stepped through
+	Exclude    []string         // pages no path may pass through
+	Through    []string         // pages every path must pass through, only supported by the forward strategy
+	Categories []string         // pages between start and target must be in one of these categories, only supported by the forward strategy
+}
 
 type WikiSteps struct {
 	log          logging.Logger
-	httpClient   *http.Client
+	site         WikiSite
+	linkSource   LinkSource
 	maxSteps     int
 	stepsTimeout time.Duration
 	numWorkers   int
@@ -45,37 +92,189 @@ type wikiStepJob struct {
 
 type wikiStepToolbelt struct {
 	Target      string
-	Timeout     <-chan time.Time
 	JobCh       chan wikiStepJob
 	CompletedCh chan wikiStepJob
-	ExitCh      chan struct{}
+	MaxResults  int
+	Constraints pathConstraints
+	Cache       *linkCache
+	Monitor     *SearchMonitor
 	ErrCh       chan error
 	IdleCh      chan struct{}
 	Wg          *sync.WaitGroup
 	WgCh        chan struct{}
 }
 
-func NewWikistepsService(log logging.Logger, maxSteps int, stepsTimeout time.Duration, numWorkers int) *WikiSteps {
+func NewWikistepsService(log logging.Logger, site WikiSite, maxSteps int, stepsTimeout time.Duration, numWorkers int) *WikiSteps {
+	return NewWikistepsServiceWithSource(log, site, NewHttpLinkSource(log, site, DefaultPolitenessPolicy()), maxSteps, stepsTimeout, numWorkers)
+}
+
+func NewWikistepsServiceWithSource(log logging.Logger, site WikiSite, linkSource LinkSource, maxSteps int, stepsTimeout time.Duration, numWorkers int) *WikiSteps {
 	return &WikiSteps{
 		log,
-		&http.Client{},
+		site,
+		linkSource,
 		maxSteps,
 		stepsTimeout,
 		numWorkers,
 	}
 }
 
-func (w WikiSteps) FindValidPaths(start string, target string, steps int) ([][]string, error) {
-	if !isValidWikiStepUrl(start, target) {
-		return nil, fmt.Errorf("start or target is invalid")
+// ProbeUpstream checks the upstream of the link source, it returns false when the link source
+// has no upstream to check, such as a link graph held in memory
+func (w WikiSteps) ProbeUpstream(ctx context.Context) (bool, error) {
+	prober, ok := findSource[UpstreamProber](w.linkSource)
+	if !ok {
+		return false, nil
+	}
+	return true, prober.Probe(ctx)
+}
+
+// FindValidPaths stops the search and returns the paths found so far once ctx is done
+func (w WikiSteps) FindValidPaths(ctx context.Context, start string, target string, steps int, options SearchOptions) (SearchResult, error) {
+	return w.FindValidPathsWithMonitor(ctx, start, target, steps, options, NewSearchMonitor())
+}
+
+// FindValidPathsWithMonitor reports progress to the monitor while searching, cancelling
+// the monitor or ctx stops the search and returns the paths found so far.
+// Paths run between the canonical pages start and target redirect to.
+func (w WikiSteps) FindValidPathsWithMonitor(ctx context.Context, start string, target string, steps int, options SearchOptions, monitor *SearchMonitor) (SearchResult, error) {
+	if err := w.ValidateSearch(start, target, steps, options); err != nil {
+		return SearchResult{}, err
+	}
+	w = w.withOptions(options) // w is a copy limited to this search
+	strategy, maxResults := options.Strategy, options.MaxResults
+
+	ctx, cancel := context.WithTimeout(ctx, w.stepsTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-monitor.Cancelled():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	cache := newLinkCache(w.linkSource, w.site, options.Extraction)
+	start, target, err := w.resolveSearchPages(ctx, start, target, cache)
+	if err != nil {
+		return SearchResult{}, searchFailed(ctx, err, "error when resolving the start and target")
+	}
+	excluded, err := w.resolvePages(ctx, options.Exclude)
+	if err != nil {
+		return SearchResult{}, searchFailed(ctx, err, "error when resolving the excluded pages")
+	}
+	cache.exclude(slices.DeleteFunc(excluded, func(p string) bool { return p == start || p == target })...)
+	constraints, err := w.newPathConstraints(ctx, options)
+	if err != nil {
+		return SearchResult{}, searchFailed(ctx, err, "error when applying the path constraints")
+	}
+	monitor.start(ctx, cache)
+	searchesStarted.WithLabelValues(strategy.String()).Inc()
+	var paths [][]string
+	var minimal bool
+	switch strategy {
+	case ForwardStrategy:
+		paths, err = w.findForwardPaths(ctx, start, target, steps, maxResults, constraints, cache, monitor)
+	case BidirectionalStrategy:
+		paths, minimal, err = w.findBidirectionalPaths(ctx, start, target, steps, maxResults, cache, monitor)
+	case ShortestStrategy:
+		paths, minimal, err = w.findShortestPaths(ctx, start, target, steps, maxResults, cache, monitor)
+	case BestFirstStrategy:
+		paths, err = w.findBestFirstPaths(ctx, start, target, steps, maxResults, cache, monitor)
+	}
+
+	w.log.Debug("WikiSteps link cache served the search", "hits", cache.Hits(), "misses", cache.Misses())
+	result := SearchResult{
+		Paths:       paths,
+		Minimal:     minimal,
+		TimedOut:    errors.Is(ctx.Err(), context.DeadlineExceeded),
+		Cancelled:   errors.Is(ctx.Err(), context.Canceled),
+		CacheHits:   cache.Hits(),
+		CacheMisses: cache.Misses(),
+	}
+	switch {
+	case err != nil:
+		return result, searchFailed(ctx, err, "error when searching for paths")
+	case result.TimedOut && len(paths) == 0:
+		return result, newSearchError(ErrTimeout, "", nil, "no path was found within %s", w.stepsTimeout)
+	}
+	return result, nil
+}
+
+func (w WikiSteps) ValidateSearch(start string, target string, steps int, options SearchOptions) error {
+	strategy, maxResults, policy := options.Strategy, options.MaxResults, options.Extraction
+
+	if !w.site.isValidWikiStepUrl(w.site.CanonicalUrl(start)) {
+		return newSearchError(ErrInvalidUrl, "start", nil, "start must be an article of %s", w.site.Host+w.site.ArticlePath)
+	}
+
+	if !w.site.isValidWikiStepUrl(w.site.CanonicalUrl(target)) || !sameSite(start, target) {
+		return newSearchError(ErrInvalidUrl, "target", nil, "target must be an article of %s", w.site.Host+w.site.ArticlePath)
 	}
 
 	if steps > w.maxSteps || steps < 0 {
-		return nil, fmt.Errorf("steps cannot be negative")
+		return newSearchError(ErrStepsOutOfRange, "steps", nil, "steps must be between 0 and %d", w.maxSteps)
+	}
+
+	if strategy < ForwardStrategy || strategy > BestFirstStrategy {
+		return newSearchError(ErrInvalidOption, "strategy", nil, "unknown search strategy %d", strategy)
+	}
+
+	if maxResults < 0 {
+		return newSearchError(ErrInvalidOption, "maxResults", nil, "max results cannot be negative")
+	}
+
+	if options.Timeout < 0 {
+		return newSearchError(ErrInvalidOption, "timeout", nil, "timeout cannot be negative")
+	}
+
+	if options.NumWorkers < 0 {
+		return newSearchError(ErrInvalidOption, "workers", nil, "workers cannot be negative")
+	}
+
+	for _, url := range options.Exclude {
+		url = w.site.CanonicalUrl(url)
+		if !w.site.isValidWikiStepUrl(url) {
+			return newSearchError(ErrInvalidUrl, "exclude", nil, "excluded page %s must be an article of %s", url, w.site.Host+w.site.ArticlePath)
+		}
+		if url == w.site.CanonicalUrl(start) || url == w.site.CanonicalUrl(target) {
+			return newSearchError(ErrInvalidOption, "exclude", nil, "the start and target cannot be excluded")
+		}
 	}
 
+	if err := w.validateConstraints(start, target, steps, options); err != nil {
+		return err
+	}
+
+	if policy < AllLinks || policy > LeadLinks {
+		return newSearchError(ErrInvalidOption, "extraction", nil, "unknown extraction policy %d", policy)
+	}
+
+	if !supportsExtractionPolicy(w.linkSource, policy) {
+		return newSearchError(ErrUnsupportedOption, "extraction", nil, "link source does not support extraction policy %s", policy)
+	}
+
+	if strategy == BidirectionalStrategy && policy != AllLinks {
+		return newSearchError(ErrUnsupportedOption, "extraction", nil, "the bidirectional strategy follows backlinks, which cannot be limited by extraction policy %s", policy)
+	}
+	return nil
+}
+
+// withOptions caps the timeout and worker count of the service by those of the options
+func (w WikiSteps) withOptions(options SearchOptions) WikiSteps {
+	if options.Timeout > 0 {
+		w.stepsTimeout = min(w.stepsTimeout, options.Timeout)
+	}
+	if options.NumWorkers > 0 {
+		w.numWorkers = min(w.numWorkers, options.NumWorkers)
+	}
+	return w
+}
+
+func (w WikiSteps) findForwardPaths(ctx context.Context, start string, target string, steps int, maxResults int, constraints pathConstraints, cache *linkCache, monitor *SearchMonitor) ([][]string, error) {
 	w.log.Trace("Initializing resources...")
-	exitCh := make(chan struct{})
+	workerCtx, cancelWorkers := context.WithCancel(ctx)
+	defer cancelWorkers()
 	errCh := make(chan error)
 	jobCh := make(chan wikiStepJob, int(math.Pow(10.0, float64(steps))))
 	completedCh := make(chan wikiStepJob, 1000*w.numWorkers)
@@ -90,24 +289,26 @@ func (w WikiSteps) FindValidPaths(start string, target string, steps int) ([][]s
 	startingJob.NumStepsRemaining = steps
 	startingJob.CompletedCh = completedCh
 	jobCh <- startingJob
+	monitor.jobQueued(0)
 
 	w.log.Trace("Initializing wait group...")
 	var wg sync.WaitGroup
 	wg.Add(w.numWorkers)
 	go func() {
 		wg.Wait()
+		idleWorkers.Add(-float64(len(idleCh))) // workers that exited while idle
 		close(wgCh)
 	}()
 
-	timeout := time.After(w.stepsTimeout)
-
 	w.log.Trace("Initializing toolbelt...")
 	toolbelt := wikiStepToolbelt{
 		Target:      target,
-		Timeout:     timeout,
+		MaxResults:  maxResults,
+		Constraints: constraints,
 		JobCh:       jobCh,
 		CompletedCh: completedCh,
-		ExitCh:      exitCh,
+		Cache:       cache,
+		Monitor:     monitor,
 		ErrCh:       errCh,
 		IdleCh:      idleCh,
 		Wg:          &wg,
@@ -117,27 +318,35 @@ func (w WikiSteps) FindValidPaths(start string, target string, steps int) ([][]s
 	w.log.Trace("Starting workers...")
 	workerNames := util.RandomNames(w.numWorkers/100, w.numWorkers)
 	for i := 0; i < w.numWorkers; i += 1 {
-		go w.wikiStepWorker(workerNames[i], toolbelt)
-		w.log.Debug(fmt.Sprintf("Started worker %s", workerNames[i]))
+		go w.wikiStepWorker(workerCtx, workerNames[i], toolbelt)
+		w.log.Debug("Started worker", "worker", workerNames[i])
 	}
 
-	results, err := w.wikiStepSupervisor(toolbelt)
+	results, err := w.wikiStepSupervisor(ctx, toolbelt)
+	cancelWorkers()
 	if err != nil {
 		return results, fmt.Errorf("error in wikiStepSupervisor; %w", err)
 	}
 
-	return append(results, w.wikiStepCleanup(toolbelt)...), nil
+	return append(results, w.wikiStepCleanup(toolbelt, len(results))...), nil
 }
 
-func (w WikiSteps) wikiStepCleanup(toolbelt wikiStepToolbelt) [][]string {
+func (w WikiSteps) wikiStepCleanup(toolbelt wikiStepToolbelt, numFound int) [][]string {
 	results := make([][]string, 0)
 	for {
 		select {
 		case completedJob := <-toolbelt.CompletedCh:
 			for _, url := range completedJob.LastPathUrls {
-				if url == toolbelt.Target {
+				if toolbelt.MaxResults > 0 && numFound+len(results) >= toolbelt.MaxResults {
+					break
+				}
+				if url != toolbelt.Target {
+					continue
+				}
+				if path := append(slices.Clone(completedJob.Path), url); toolbelt.Constraints.allowsPath(path) {
 					w.log.Debug("WikiSteps found a valid path to the target in cleanup")
-					results = append(results, append(completedJob.Path, url))
+					results = append(results, path)
+					toolbelt.Monitor.pathFound(path)
 				}
 			}
 		case <-toolbelt.WgCh:
@@ -146,234 +355,149 @@ func (w WikiSteps) wikiStepCleanup(toolbelt wikiStepToolbelt) [][]string {
 	}
 }
 
-func (w WikiSteps) wikiStepSupervisor(toolbelt wikiStepToolbelt) ([][]string, error) {
+// wikiStepSupervisor returns once ctx is done, a worker fails or all workers are idle,
+// the caller is responsible for signaling the workers to exit
+func (w WikiSteps) wikiStepSupervisor(ctx context.Context, toolbelt wikiStepToolbelt) ([][]string, error) {
 	results := make([][]string, 0)
 	for {
 		select {
-		case <-toolbelt.Timeout:
-			w.log.Info(fmt.Sprintf("WikiSteps timed out after %.0f seconds, signaling exit...", w.stepsTimeout.Seconds()))
-			close(toolbelt.ExitCh)
+		case <-ctx.Done():
+			w.logSearchStopped(ctx)
 			return results, nil
 
 		case err := <-toolbelt.ErrCh:
 			w.log.Info("WikiSteps is signaling exit after encountering an error...")
-			close(toolbelt.ExitCh)
 			return results, err
 
 		case <-time.After(3 * time.Second):
 			if len(toolbelt.IdleCh) >= w.numWorkers && len(toolbelt.JobCh) == 0 {
 				w.log.Debug("WikiSteps workers are all idle and job queue is empty, signaling exit...")
-				close(toolbelt.ExitCh)
 				return results, nil
 			}
 
 		case completedJob := <-toolbelt.CompletedCh:
+			toolbelt.Monitor.jobCompleted()
 			for _, url := range completedJob.LastPathUrls {
 				if url == toolbelt.Target {
+					path := append(slices.Clone(completedJob.Path), url)
+					if !toolbelt.Constraints.allowsPath(path) {
+						w.log.Debug("WikiSteps found a path to the target that violates the path constraints")
+						continue
+					}
 					w.log.Debug("WikiSteps found a valid path to the target")
-					results = append(results, append(completedJob.Path, url))
+					results = append(results, path)
+					toolbelt.Monitor.pathFound(path)
+					if toolbelt.MaxResults > 0 && len(results) >= toolbelt.MaxResults {
+						w.log.Debug("WikiSteps found enough paths, signaling exit...", "paths", len(results))
+						return results, nil
+					}
 
-				} else if completedJob.NumStepsRemaining-1 > 0 {
+				} else if completedJob.NumStepsRemaining-1 <= 0 {
+					w.log.Debug("WikiSteps dead end! A path ran out of steps")
+
+				} else if !toolbelt.Constraints.allowsStep(completedJob.Path, url, completedJob.NumStepsRemaining-1) {
+					w.log.Trace("WikiSteps dropped a step violating the path constraints", "link", url)
+
+				} else {
 					w.log.Debug("WikiSteps did not find a valid path to target yet, resubmitting job")
 					var j wikiStepJob
-					j.Path = append(completedJob.Path, url)
+					j.Path = append(slices.Clone(completedJob.Path), url)
 					j.NumStepsRemaining = completedJob.NumStepsRemaining - 1
 					j.CompletedCh = toolbelt.CompletedCh
-					toolbelt.JobCh <- j
-
-				} else {
-					w.log.Debug("WikiSteps dead end! A path ran out of steps")
+					select {
+					case toolbelt.JobCh <- j:
+						toolbelt.Monitor.jobQueued(len(completedJob.Path))
+					case <-ctx.Done(): // the workers stopped, so the job queue may never drain
+						w.logSearchStopped(ctx)
+						return results, nil
+					}
 				}
 			}
 		}
 	}
 }
 
-func (w WikiSteps) wikiStepWorker(name string, toolbelt wikiStepToolbelt) {
+func (w WikiSteps) wikiStepWorker(ctx context.Context, name string, toolbelt wikiStepToolbelt) {
 	defer toolbelt.Wg.Done()
+	log := w.log.With("worker", name)
 	for {
 		select {
-		case <-toolbelt.ExitCh:
-			w.log.Debug(fmt.Sprintf("Worker %s received exit signal, closing...", name))
+		case <-ctx.Done():
+			log.Debug("Worker received exit signal, closing...")
 			return
 		case job := <-toolbelt.JobCh:
 			select {
 			case <-toolbelt.IdleCh:
-				w.log.Trace(fmt.Sprintf("Worker %s is now active, removed from IdleCh", name))
+				idleWorkers.Dec()
+				log.Trace("Worker is now active, removed from IdleCh")
 			default: // If IdleCh is already empty, no action needed
 			}
-			w.log.Debug(fmt.Sprintf("Worker %s started a new job", name))
-			job, err := w.doWikiStepJob(name, job, toolbelt.ExitCh)
+			log.Debug("Worker started a new job")
+			jobsInFlight.Inc()
+			job, err := w.doWikiStepJob(ctx, name, job, toolbelt.Cache)
+			jobsInFlight.Dec()
+			if ctx.Err() != nil {
+				log.Debug("Worker received exit signal during a job, closing...")
+				return
+			}
 			if err != nil {
-				toolbelt.ErrCh <- err
+				select {
+				case toolbelt.ErrCh <- err:
+				case <-ctx.Done():
+				}
 				continue
 			}
-			toolbelt.CompletedCh <- job
-			w.log.Debug(fmt.Sprintf("Worker %s completed a job", name))
+			select {
+			case toolbelt.CompletedCh <- job:
+			case <-ctx.Done():
+				log.Debug("Worker received exit signal while completing a job, closing...")
+				return
+			}
+			log.Debug("Worker completed a job")
 			select {
 			case toolbelt.IdleCh <- struct{}{}:
-				w.log.Trace(fmt.Sprintf("Worker %s is now idle, added to IdleCh", name))
+				idleWorkers.Inc()
+				log.Trace("Worker is now idle, added to IdleCh")
 			default: // If IdleCh is full, no action needed
 			}
 		}
 	}
 }
 
-func (w WikiSteps) doWikiStepJob(workerName string, job wikiStepJob, exitCh <-chan struct{}) (wikiStepJob, error) {
+func (w WikiSteps) doWikiStepJob(ctx context.Context, workerName string, job wikiStepJob, cache *linkCache) (wikiStepJob, error) {
 	if len(job.Path) == 0 {
 		return job, fmt.Errorf("worker %s's path slice is empty", workerName) // should never happen
 	}
 
 	nextUrl := job.Path[len(job.Path)-1] // isolate the next URL to fetch data for
+	log := w.log.With("worker", workerName, "url", nextUrl)
 
-	resp, err := w.callWikipedia(workerName, nextUrl, exitCh)
-	if err != nil {
-		return job, fmt.Errorf("worker %s encountered an error when calling Wikipedia; %w", workerName, err)
-	}
-
-	if resp == nil {
-		return job, nil
-	} else {
-		defer resp.Close()
-	}
-
-	// parsing the resposne body and extracting any valid URLs
-	w.log.Debug(fmt.Sprintf("Worker %s is extracting URLs from the response body of URL %s...", workerName, nextUrl))
-	urls, err := w.extractWikiLinks(resp, workerName)
+	urls, err := cache.Links(ctx, workerName, nextUrl)
 	if err != nil {
-		return job, fmt.Errorf("worker %s encountered an error when extracting URLs from the response body for URL %s; %w", workerName, nextUrl, err)
+		return job, fmt.Errorf("worker %s encountered an error when fetching links for URL %s; %w", workerName, nextUrl, err)
 	}
 
-	w.log.Trace(fmt.Sprintf("Worker %s found %d preliminary unique URLs in resoponse body for URL %s. Removing URLs present in current path...", workerName, len(urls), nextUrl))
+	log.Trace("Worker found preliminary unique URLs in resoponse body. Removing URLs present in current path...", "links", len(urls))
 	isDuplicateStep := func(url string) bool {
-		for _, p := range job.Path {
-			if url == p {
-				return true
-			}
+		if slices.Contains(job.Path, url) {
+			log.Trace("Worker found a preliminary URL that already exists in path. Removing URL...", "link", url)
+			return true
 		}
 		return false
 	}
-	for i, url := range urls {
-		if isDuplicateStep(url) {
-			w.log.Trace(fmt.Sprintf("Worker %s found a preliminary URL that already exists in path. Removing URL %s...", workerName, url))
-			urls = slices.Delete(urls, i, i+1)
-		}
-	}
-	w.log.Debug(fmt.Sprintf("Worker %s found %d unique URLs in response body", workerName, len(urls)))
+	urls = slices.DeleteFunc(slices.Clone(urls), isDuplicateStep) // cloned since the cached slice is shared with other jobs
+	log.Debug("Worker found unique URLs in response body", "links", len(urls))
 
 	// updating and returning completed job
 	job.LastPathUrls = urls
 	return job, nil
 }
 
-func (w WikiSteps) callWikipedia(workerName string, url string, exitCh <-chan struct{}) (io.ReadCloser, error) {
-	// requesting data from Wikipedia
-	w.log.Debug(fmt.Sprintf("Worker %s is waiting for semaphore aquisition...", workerName))
-	select {
-	case httpSem <- struct{}{}:
-		w.log.Debug(fmt.Sprintf("Worker %s aquired semaphore.", workerName))
-		defer func() { <-httpSem }()
-	case <-exitCh:
-		w.log.Debug(fmt.Sprintf("Worker %s recieved exit signal while waiting for semaphore aquisition.", workerName))
-		return nil, nil
-	}
-
-	w.log.Trace(fmt.Sprintf("Worker %s is building GET request for URL %s", workerName, url))
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("worker %s encountered an error when building GET request for URL: %s; %w", workerName, url, err)
-	}
-
-	w.log.Debug(fmt.Sprintf("Worker %s is executing a GET request for URL %s", workerName, url))
-	resp, err := w.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("worker %s encountered an error when executing GET for URL %s; %w", workerName, url, err)
-	}
-
-	if resp.Body == nil {
-		return nil, fmt.Errorf("worker %s's response body is nil for URL %s", workerName, url)
-	}
-	w.log.Trace(fmt.Sprintf("Worker %s's GET request for URL %s returned a body of size %d bytes", workerName, url, resp.ContentLength))
-	return resp.Body, nil
-}
-
-func (w WikiSteps) extractWikiLinks(body io.ReadCloser, workerName string) ([]string, error) {
-	w.log.Trace(fmt.Sprintf("Worker %s is parsing response body to html node...", workerName))
-	root, err := html.Parse(body)
-	if err != nil {
-		return nil, fmt.Errorf("error when parsing html response body; %w", err)
-	}
-
-	urlSet := make(map[string]struct{}) // set to keep only unique URLs in the path
-
-	var traverse func(n *html.Node) // defining function to traverse nodes
-	traverse = func(n *html.Node) {
-		if n == nil {
-			return
-		}
-
-		// checking if node is element <a> and has a valid Wikipedia URI
-		if n.Type == html.ElementNode && n.Data == "a" {
-			for _, a := range n.Attr {
-				if a.Key == "href" && isValidWikistepUri(a.Val) {
-					url := WikipediaDomain + a.Val
-					if _, exists := urlSet[url]; exists {
-						w.log.Trace(fmt.Sprintf("Worker %s's node %p has a valid duplicate URL: '%s'. Unique URL set length: %d", workerName, n, url, len(urlSet)))
-					} else {
-						urlSet[url] = struct{}{}
-						w.log.Trace(fmt.Sprintf("Worker %s's node %p has a valid new URL: '%s'. Unique URL set length: %d", workerName, n, url, len(urlSet)))
-					}
-				}
-			}
-		}
-
-		// recursive call to traverse children
-		for c := range n.ChildNodes() {
-			traverse(c)
-		}
-	}
-
-	traverse(root) // call the traverse fucntion on the root node
-
-	urls := make([]string, len(urlSet))
-	i := 0
-	for k, _ := range urlSet {
-		urls[i] = k
-		i += 1
-	}
-	return urls, nil
-}
-
-func isValidWikiStepUrl(urls ...string) bool {
-	for _, s := range urls {
-		if !strings.HasPrefix(s, WikipediaDomain+WikiPrefix) {
-			return false
-		}
-		if reWikiBlockPrefix.MatchString(s) {
-			return false
-		}
-		for _, b := range wikiBlockPrefixList {
-			if strings.HasPrefix(s, b) {
-				return false
-			}
-		}
-	}
-	return true
-}
-
-func isValidWikistepUri(uris ...string) bool {
-	for _, s := range uris {
-		if !strings.HasPrefix(s, WikiPrefix) {
-			return false
-		}
-		if reWikiBlockPrefix.MatchString(s) {
-			return false
-		}
-		for _, b := range wikiBlockPrefixList {
-			if strings.HasPrefix(s, b) {
-				return false
-			}
-		}
+func (w WikiSteps) logSearchStopped(ctx context.Context) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		searchesTimedOut.Inc()
+		w.log.Info("WikiSteps timed out, signaling exit...", "timeout", w.stepsTimeout.String())
+	} else {
+		w.log.Info("WikiSteps was cancelled, signaling exit...")
 	}
-	return true
 }
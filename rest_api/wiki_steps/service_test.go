@@ -0,0 +1,310 @@
+package wikiSteps
+
+import (
+	"app/rest_api/logging"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Trace(msg string, fields ...any)     {}
+func (nopLogger) Debug(msg string, fields ...any)     {}
+func (nopLogger) Info(msg string, fields ...any)      {}
+func (nopLogger) Error(msg string, fields ...any)     {}
+func (nopLogger) Fatal(msg string, fields ...any)     {}
+func (l nopLogger) With(fields ...any) logging.Logger { return l }
+
+var testSite WikiSite = Wikipedia("en")
+
+func page(title string) string {
+	return testSite.Host + testSite.ArticlePath + title
+}
+
+func testLinkSource() *MemoryLinkSource {
+	return NewMemoryLinkSource(map[string][]string{
+		page("A"): {page("B"), page("C")},
+		page("B"): {page("C")},
+		page("C"): {page("D")},
+		page("D"): {},
+	})
+}
+
+func sortedPaths(paths [][]string) []string {
+	joined := make([]string, len(paths))
+	for i, p := range paths {
+		joined[i] = strings.Join(p, " ")
+	}
+	slices.Sort(joined)
+	return joined
+}
+
+func TestFindValidPathsForward(t *testing.T) {
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, testLinkSource(), 5, 10*time.Second, 1)
+	result, err := service.FindValidPaths(context.Background(), page("A"), page("D"), 3, SearchOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := sortedPaths([][]string{
+		{page("A"), page("B"), page("C"), page("D")},
+		{page("A"), page("C"), page("D")},
+	})
+	if paths := sortedPaths(result.Paths); !slices.Equal(paths, expected) {
+		t.Errorf("Expected: %v Actual: %v", expected, paths)
+	}
+}
+
+func TestFindValidPathsBidirectional(t *testing.T) {
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, testLinkSource(), 5, 10*time.Second, 4)
+	result, err := service.FindValidPaths(context.Background(), page("A"), page("D"), 3, SearchOptions{Strategy: BidirectionalStrategy})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := sortedPaths([][]string{{page("A"), page("C"), page("D")}})
+	if paths := sortedPaths(result.Paths); !slices.Equal(paths, expected) {
+		t.Errorf("Expected: %v Actual: %v", expected, paths)
+	}
+}
+
+type countingLinkSource struct {
+	LinkSource
+	calls atomic.Int64
+}
+
+func (c *countingLinkSource) Links(ctx context.Context, workerName string, url string) ([]string, error) {
+	c.calls.Add(1)
+	return c.LinkSource.Links(ctx, workerName, url)
+}
+
+func TestLinkCache(t *testing.T) {
+	source := &countingLinkSource{LinkSource: testLinkSource()}
+	cache := newLinkCache(source, testSite, AllLinks)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i += 1 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.Links(context.Background(), "test", page("A"))
+		}()
+	}
+	wg.Wait()
+
+	if calls := source.calls.Load(); calls != 1 {
+		t.Errorf("Expected: %d Actual: %d", 1, calls)
+	}
+	if cache.Hits() != 9 || cache.Misses() != 1 {
+		t.Errorf("Expected: 9 hits, 1 miss Actual: %d hits, %d misses", cache.Hits(), cache.Misses())
+	}
+}
+
+func TestHtmlDirLinkSource(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"A.html":       `<html><body><a href="/wiki/B">B</a><a href="/wiki/AC/DC">AC/DC</a><a href="/wiki/Help:Contents">Help</a></body></html>`,
+		"AC%2FDC.html": `<html><body><a href="/wiki/B">B</a></body></html>`,
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	source := NewHtmlDirLinkSource(nopLogger{}, testSite, dir)
+
+	links, err := source.Links(context.Background(), "test", page("A"))
+	slices.Sort(links)
+	if expected := []string{page("AC/DC"), page("B")}; err != nil || !slices.Equal(links, expected) {
+		t.Errorf("Expected: %v Actual: %v (%v)", expected, links, err)
+	}
+
+	backlinks, err := source.Backlinks(context.Background(), "test", page("B"))
+	slices.Sort(backlinks)
+	if expected := []string{page("A"), page("AC/DC")}; err != nil || !slices.Equal(backlinks, expected) {
+		t.Errorf("Expected: %v Actual: %v (%v)", expected, backlinks, err)
+	}
+
+	links, err = source.Links(context.Background(), "test", page("Missing"))
+	if err != nil || links == nil || len(links) != 0 {
+		t.Errorf("Expected no links for a missing page, Actual: %v (%v)", links, err)
+	}
+}
+
+func TestSearchMonitorStreamPaths(t *testing.T) {
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, testLinkSource(), 5, 10*time.Second, 4)
+	monitor := NewSearchMonitor()
+	pathCh := monitor.StreamPaths()
+
+	result, err := service.FindValidPathsWithMonitor(context.Background(), page("A"), page("D"), 3, SearchOptions{Strategy: BidirectionalStrategy}, monitor)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(pathCh) != len(result.Paths) {
+		t.Fatalf("Expected: %d streamed paths Actual: %d", len(result.Paths), len(pathCh))
+	}
+	if path := <-pathCh; !slices.Equal(path, result.Paths[0]) {
+		t.Errorf("Expected: %v Actual: %v", result.Paths[0], path)
+	}
+	if progress := monitor.Progress(); progress.PathsFound != 1 || progress.PagesFetched == 0 {
+		t.Errorf("Expected: 1 path found and pages fetched Actual: %+v", progress)
+	}
+}
+
+func TestSearchMonitorStalledReader(t *testing.T) {
+	pages := map[string][]string{page("S"): {}}
+	for i := range 150 {
+		hub := page(fmt.Sprintf("X%d", i))
+		pages[page("S")] = append(pages[page("S")], hub)
+		pages[hub] = []string{page("T")}
+	}
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, NewMemoryLinkSource(pages), 5, 10*time.Second, 4)
+	monitor := NewSearchMonitor()
+	monitor.StreamPaths() // never read, so the buffer fills up
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	began := time.Now()
+	if _, err := service.FindValidPathsWithMonitor(ctx, page("S"), page("T"), 2, SearchOptions{Strategy: ShortestStrategy}, monitor); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if elapsed := time.Since(began); elapsed > 2*time.Second {
+		t.Errorf("Expected the search to return once its context is done, took %s", elapsed)
+	}
+}
+
+func TestFindValidPathsContextCancelled(t *testing.T) {
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, testLinkSource(), 5, 10*time.Second, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	began := time.Now()
+	if _, err := service.FindValidPaths(ctx, page("A"), page("Missing"), 3, SearchOptions{}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if elapsed := time.Since(began); elapsed > time.Second {
+		t.Errorf("Expected the cancelled search to return immediately, took %s", elapsed)
+	}
+}
+
+// stalledLinkSource blocks on the links of every page but start until ctx is done
+type stalledLinkSource struct {
+	*MemoryLinkSource
+	start string
+}
+
+func (s stalledLinkSource) Links(ctx context.Context, workerName string, url string) ([]string, error) {
+	if url == s.start {
+		return s.MemoryLinkSource.Links(ctx, workerName, url)
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestFindValidPathsCancelledWithFullQueue(t *testing.T) {
+	pages := map[string][]string{page("S"): {}}
+	for i := range 500 {
+		pages[page("S")] = append(pages[page("S")], page(fmt.Sprintf("X%d", i)))
+	}
+	source := stalledLinkSource{NewMemoryLinkSource(pages), page("S")}
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, source, 5, 300*time.Millisecond, 2)
+
+	// the 500 jobs of the second step do not fit the queue of 10^2 jobs, and the workers never take them
+	done := make(chan error, 1)
+	go func() {
+		_, err := service.FindValidPaths(context.Background(), page("S"), page("T"), 2, SearchOptions{})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrTimeout) {
+			t.Errorf("Expected: %v Actual: %v", ErrTimeout, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the search to return once it timed out")
+	}
+}
+
+func TestFindValidPathsShortest(t *testing.T) {
+	source := NewMemoryLinkSource(map[string][]string{
+		page("S"):  {page("X1"), page("X2"), page("X3"), page("Y")},
+		page("X1"): {page("T")},
+		page("X2"): {page("T")},
+		page("X3"): {page("T")},
+		page("Y"):  {page("Z")},
+		page("Z"):  {page("T")},
+	})
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, source, 5, 10*time.Second, 4)
+
+	result, err := service.FindValidPaths(context.Background(), page("S"), page("T"), 4, SearchOptions{Strategy: ShortestStrategy})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(result.Paths) != 3 || !result.Minimal {
+		t.Errorf("Expected: 3 minimal paths Actual: %v (minimal: %t)", result.Paths, result.Minimal)
+	}
+	for _, path := range result.Paths {
+		if len(path) != 3 {
+			t.Errorf("Expected a path of 2 steps Actual: %v", path)
+		}
+	}
+
+	result, err = service.FindValidPaths(context.Background(), page("S"), page("T"), 4, SearchOptions{Strategy: ShortestStrategy, MaxResults: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(result.Paths) != 2 || !result.Minimal {
+		t.Errorf("Expected: 2 minimal paths Actual: %v (minimal: %t)", result.Paths, result.Minimal)
+	}
+
+	result, err = service.FindValidPaths(context.Background(), page("S"), page("T"), 1, SearchOptions{Strategy: ShortestStrategy})
+	if err != nil || len(result.Paths) != 0 || result.Minimal {
+		t.Errorf("Expected no paths within 1 step Actual: %v (minimal: %t, %v)", result.Paths, result.Minimal, err)
+	}
+}
+
+func TestFindValidPathsOptions(t *testing.T) {
+	service := NewWikistepsServiceWithSource(nopLogger{}, testSite, testLinkSource(), 5, 10*time.Second, 4)
+
+	result, err := service.FindValidPaths(context.Background(), page("A"), page("D"), 3, SearchOptions{Strategy: ShortestStrategy, Exclude: []string{page("B")}})
+	if expected := [][]string{{page("A"), page("C"), page("D")}}; err != nil || !slices.EqualFunc(result.Paths, expected, slices.Equal) {
+		t.Errorf("Expected: %v Actual: %v (%v)", expected, result.Paths, err)
+	}
+
+	result, err = service.FindValidPaths(context.Background(), page("A"), page("D"), 3, SearchOptions{Strategy: BidirectionalStrategy, Exclude: []string{page("C")}})
+	if err != nil || len(result.Paths) != 0 {
+		t.Errorf("Expected: no paths Actual: %v (%v)", result.Paths, err)
+	}
+
+	invalid := map[string]SearchOptions{
+		"timeout": {Timeout: -time.Second},
+		"workers": {NumWorkers: -1},
+		"exclude": {Exclude: []string{page("A")}},
+	}
+	for field, options := range invalid {
+		var searchErr *SearchError
+		if err = service.ValidateSearch(page("A"), page("D"), 3, options); !errors.As(err, &searchErr) || searchErr.Field != field {
+			t.Errorf("Expected: an error for %s Actual: %v", field, err)
+		}
+	}
+
+	// the options cannot raise the limits of the service
+	limited := service.withOptions(SearchOptions{Timeout: time.Hour, NumWorkers: 100})
+	if limited.stepsTimeout != 10*time.Second || limited.numWorkers != 4 {
+		t.Errorf("Expected: 10s and 4 workers Actual: %s and %d workers", limited.stepsTimeout, limited.numWorkers)
+	}
+	limited = service.withOptions(SearchOptions{Timeout: time.Second, NumWorkers: 2})
+	if limited.stepsTimeout != time.Second || limited.numWorkers != 2 {
+		t.Errorf("Expected: 1s and 2 workers Actual: %s and %d workers", limited.stepsTimeout, limited.numWorkers)
+	}
+}
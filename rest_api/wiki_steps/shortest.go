@@ -0,0 +1,67 @@
+package wikiSteps
+
+import (
+	"app/rest_api/util"
+	"context"
+	"fmt"
+	"slices"
+)
+
+// findShortestPaths expands forward one breadth-first level at a time and stops after the first
+// level reaching the target, or as soon as maxResults paths to the target are known.
+// Since every earlier level was fully expanded, any path found is a shortest path.
+func (w WikiSteps) findShortestPaths(ctx context.Context, start string, target string, steps int, maxResults int, cache *linkCache, monitor *SearchMonitor) ([][]string, bool, error) {
+	if start == target {
+		monitor.pathFound([]string{start})
+		return [][]string{{start}}, true, nil
+	}
+
+	w.log.Trace("Initializing shortest path resources...")
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // signals the workers of an unfinished level to exit
+	workerNames := util.RandomNames(w.numWorkers/100, w.numWorkers)
+
+	parents := map[string][]string{start: nil} // page -> pages one step closer to start
+	depths := map[string]int{start: 0}
+	frontier := []string{start}
+
+	for level := 0; level < steps && len(frontier) > 0; level++ {
+		numFound := 0
+		enough := func(url string, links []string) bool {
+			if maxResults <= 0 || !slices.Contains(links, target) {
+				return false
+			}
+			numFound += len(walkPaths(url, parents, maxResults-numFound))
+			return numFound >= maxResults
+		}
+
+		for range frontier {
+			monitor.jobQueued(level + 1)
+		}
+		w.log.Debug("WikiSteps is expanding the pages of a level", "pages", len(frontier), "level", level)
+		expanded, err := w.expandFrontier(ctx, workerNames, frontier, forwardDirection, cache, monitor, enough)
+		stopped := ctx.Err() != nil
+		if stopped {
+			w.logSearchStopped(ctx)
+		} else if err != nil {
+			return [][]string{}, false, fmt.Errorf("error when expanding frontier; %w", err)
+		}
+
+		frontier = mergeFrontier(expanded, parents, depths)
+		if _, found := depths[target]; found {
+			paths := walkPaths(target, parents, maxResults)
+			w.log.Debug("WikiSteps found the shortest paths", "paths", len(paths), "steps", level+1)
+			for _, path := range paths {
+				monitor.pathFound(path)
+			}
+			return paths, true, nil
+		}
+
+		if stopped {
+			return [][]string{}, false, nil
+		}
+	}
+
+	w.log.Debug("WikiSteps dead end! No path reached the target")
+	return [][]string{}, false, nil
+}
@@ -0,0 +1,165 @@
+package wikiSteps
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const WikipediaApiPath string = "/w/api.php"
+
+var (
+	defaultBlockedTitles       []string       = []string{"Main_Page"}
+	defaultBlockedTitlePattern *regexp.Regexp = regexp.MustCompile(`^\w+:.*`) // titles in a namespace
+)
+
+// wfUrlencode leaves these characters unescaped in article paths
+var titleUrlUnescaper *strings.Replacer = strings.NewReplacer(
+	"%3B", ";", "%40", "@", "%24", "$", "%21", "!", "%2A", "*", "%28", "(",
+	"%29", ")", "%2C", ",", "%2F", "/", "%7E", "~", "%3A", ":",
+)
+
+// WikiSite describes the MediaWiki install a search runs on, start and target have to be
+// articles of the same site and only links to articles of that site are stepped through
+type WikiSite struct {
+	Host                string         // scheme and host, e.g. https://en.wikipedia.org
+	ArticlePath         string         // path articles are served under, e.g. /wiki/
+	ApiPath             string         // path of the MediaWiki Action API, e.g. /w/api.php
+	CapitalLinks        bool           // whether the first letter of titles is upper case, $wgCapitalLinks of the site
+	BlockedTitles       []string       // title prefixes that are never stepped through
+	BlockedTitlePattern *regexp.Regexp // titles that are never stepped through
+}
+
+// Wikipedia returns the site of the Wikipedia of a language, e.g. en or de
+func Wikipedia(language string) WikiSite {
+	return WikiSite{
+		Host:                "https://" + language + ".wikipedia.org",
+		ArticlePath:         "/wiki/",
+		ApiPath:             WikipediaApiPath,
+		CapitalLinks:        true,
+		BlockedTitles:       defaultBlockedTitles,
+		BlockedTitlePattern: defaultBlockedTitlePattern,
+	}
+}
+
+// NewWikiSite validates a site configuration, capitalLinks is off for sites with case-sensitive
+// titles such as Wiktionary and an empty blockedTitlePattern blocks every title in a namespace
+func NewWikiSite(host string, articlePath string, apiPath string, capitalLinks bool, blockedTitles []string, blockedTitlePattern string) (WikiSite, error) {
+	hostUrl, err := url.Parse(host)
+	if err != nil || (hostUrl.Scheme != "http" && hostUrl.Scheme != "https") || hostUrl.Host == "" || strings.Trim(hostUrl.Path, "/") != "" {
+		return WikiSite{}, fmt.Errorf("wiki host '%s' must be an http or https URL without a path", host)
+	}
+	if !strings.HasPrefix(articlePath, "/") || !strings.HasSuffix(articlePath, "/") {
+		return WikiSite{}, fmt.Errorf("wiki article path '%s' must start and end with '/'", articlePath)
+	}
+	if !strings.HasPrefix(apiPath, "/") {
+		return WikiSite{}, fmt.Errorf("wiki API path '%s' must start with '/'", apiPath)
+	}
+
+	pattern := defaultBlockedTitlePattern
+	if blockedTitlePattern != "" {
+		if pattern, err = regexp.Compile(blockedTitlePattern); err != nil {
+			return WikiSite{}, fmt.Errorf("invalid blocked title pattern '%s'; %w", blockedTitlePattern, err)
+		}
+	}
+
+	return WikiSite{
+		strings.TrimSuffix(host, "/"),
+		articlePath,
+		apiPath,
+		capitalLinks,
+		blockedTitles,
+		pattern,
+	}, nil
+}
+
+func (s WikiSite) ApiUrl() string {
+	return s.Host + s.ApiPath
+}
+
+// PageUrl builds the article URL of a title the way MediaWiki renders it in hrefs
+func (s WikiSite) PageUrl(title string) string {
+	escaped := url.QueryEscape(strings.ReplaceAll(title, " ", "_"))
+	return s.Host + s.ArticlePath + titleUrlUnescaper.Replace(escaped)
+}
+
+// PageTitle returns the title of an article URL
+func (s WikiSite) PageTitle(pageUrl string) string {
+	path := strings.TrimPrefix(pageUrl, s.Host+s.ArticlePath)
+	if title, err := url.PathUnescape(path); err == nil {
+		path = title
+	}
+	return strings.ReplaceAll(path, "_", " ")
+}
+
+// CanonicalUrl normalizes an article URL or URI to the URL MediaWiki links the article with:
+// the fragment and query are dropped, the title is decoded, underscores and spaces are
+// collapsed and the first letter is upper case unless the titles of the site are case-sensitive.
+// URLs outside of the site are returned as is.
+func (s WikiSite) CanonicalUrl(pageUrl string) string {
+	pageUrl, _, _ = strings.Cut(pageUrl, "#")
+	pageUrl, _, _ = strings.Cut(pageUrl, "?")
+
+	var title string
+	if t, ok := strings.CutPrefix(pageUrl, s.Host+s.ArticlePath); ok {
+		title = t
+	} else if t, ok := strings.CutPrefix(pageUrl, s.ArticlePath); ok {
+		title = t
+	} else {
+		return pageUrl
+	}
+
+	if decoded, err := url.PathUnescape(title); err == nil {
+		title = decoded
+	}
+	title = strings.Join(strings.Fields(strings.ReplaceAll(title, "_", " ")), " ")
+	if r, size := utf8.DecodeRuneInString(title); s.CapitalLinks && r != utf8.RuneError {
+		title = string(unicode.ToUpper(r)) + title[size:]
+	}
+	return s.PageUrl(title)
+}
+
+// Contains reports whether the URL is an article URL of the site
+func (s WikiSite) Contains(pageUrl string) bool {
+	return strings.HasPrefix(pageUrl, s.Host+s.ArticlePath)
+}
+
+func (s WikiSite) isValidWikiStepUrl(urls ...string) bool {
+	for _, u := range urls {
+		if !s.Contains(u) || s.isBlockedTitle(strings.TrimPrefix(u, s.Host+s.ArticlePath)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s WikiSite) isValidWikistepUri(uris ...string) bool {
+	for _, u := range uris {
+		if !strings.HasPrefix(u, s.ArticlePath) || s.isBlockedTitle(strings.TrimPrefix(u, s.ArticlePath)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s WikiSite) isBlockedTitle(title string) bool {
+	if s.BlockedTitlePattern != nil && s.BlockedTitlePattern.MatchString(title) {
+		return true
+	}
+	for _, b := range s.BlockedTitles {
+		if strings.HasPrefix(title, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameSite reports whether both URLs share scheme and host
+func sameSite(a string, b string) bool {
+	aUrl, aErr := url.Parse(a)
+	bUrl, bErr := url.Parse(b)
+	return aErr == nil && bErr == nil && aUrl.Scheme == bUrl.Scheme && strings.EqualFold(aUrl.Host, bUrl.Host)
+}
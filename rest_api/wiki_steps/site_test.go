@@ -0,0 +1,98 @@
+package wikiSteps
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCanonicalUrl(t *testing.T) {
+	tests := map[string]string{
+		page("Machine_translation"):                 page("Machine_translation"),
+		page("machine_translation"):                 page("Machine_translation"),
+		page("Machine translation"):                 page("Machine_translation"),
+		page("Machine%20translation#History"):       page("Machine_translation"),
+		page("__Machine___translation_"):            page("Machine_translation"),
+		page("C++"):                                 page("C%2B%2B"),
+		page("%C3%A9cole?action=history"):           page("%C3%89cole"),
+		"/wiki/AC/DC":                               page("AC/DC"),
+		"https://de.wikipedia.org/wiki/Maschinelle": "https://de.wikipedia.org/wiki/Maschinelle",
+	}
+	for input, expected := range tests {
+		if actual := testSite.CanonicalUrl(input); actual != expected {
+			t.Errorf("Input: %s Expected: %s Actual: %s", input, expected, actual)
+		}
+	}
+}
+
+func TestCanonicalUrlCaseSensitive(t *testing.T) {
+	site, err := NewWikiSite("https://en.wiktionary.org", "/wiki/", "/w/api.php", false, nil, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	tests := map[string]string{
+		"https://en.wiktionary.org/wiki/free_lunch":        "https://en.wiktionary.org/wiki/free_lunch",
+		"https://en.wiktionary.org/wiki/free%20lunch#Noun": "https://en.wiktionary.org/wiki/free_lunch",
+		"https://en.wiktionary.org/wiki/Free_lunch":        "https://en.wiktionary.org/wiki/Free_lunch",
+		"/wiki/%C3%A9cole": "https://en.wiktionary.org/wiki/%C3%A9cole",
+	}
+	for input, expected := range tests {
+		if actual := site.CanonicalUrl(input); actual != expected {
+			t.Errorf("Input: %s Expected: %s Actual: %s", input, expected, actual)
+		}
+	}
+}
+
+func TestNewWikiSite(t *testing.T) {
+	site, err := NewWikiSite("https://en.wiktionary.org/", "/wiki/", "/w/api.php", false, []string{"Wiktionary:Main_Page"}, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if url := site.PageUrl("free lunch"); url != "https://en.wiktionary.org/wiki/free_lunch" {
+		t.Errorf("Expected: https://en.wiktionary.org/wiki/free_lunch Actual: %s", url)
+	}
+	if site.isValidWikiStepUrl(site.PageUrl("Appendix:Glossary")) {
+		t.Errorf("Expected titles in a namespace to be blocked")
+	}
+
+	invalid := [][]string{
+		{"en.wiktionary.org", "/wiki/", "/w/api.php", ""},
+		{"https://en.wiktionary.org/w", "/wiki/", "/w/api.php", ""},
+		{"https://en.wiktionary.org", "wiki", "/w/api.php", ""},
+		{"https://en.wiktionary.org", "/wiki/", "/w/api.php", "("},
+	}
+	for _, args := range invalid {
+		if _, err = NewWikiSite(args[0], args[1], args[2], true, nil, args[3]); err == nil {
+			t.Errorf("Expected an error for %v", args)
+		}
+	}
+}
+
+func TestFindValidPathsOtherSite(t *testing.T) {
+	site := Wikipedia("de")
+	dePage := func(title string) string {
+		return site.Host + site.ArticlePath + title
+	}
+	source := NewMemoryLinkSource(map[string][]string{
+		dePage("Maschinelle_%C3%9Cbersetzung"): {dePage("Computerlinguistik"), dePage("Kategorie:Linguistik")},
+		dePage("Computerlinguistik"):           {},
+	})
+	service := NewWikistepsServiceWithSource(nopLogger{}, site, source, 5, 10*time.Second, 4)
+
+	result, err := service.FindValidPaths(context.Background(), dePage("Maschinelle_%C3%9Cbersetzung"), dePage("Computerlinguistik"), 2, SearchOptions{Strategy: ShortestStrategy})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := sortedPaths([][]string{{dePage("Maschinelle_%C3%9Cbersetzung"), dePage("Computerlinguistik")}})
+	if paths := sortedPaths(result.Paths); !slices.Equal(paths, expected) {
+		t.Errorf("Expected: %v Actual: %v", expected, paths)
+	}
+
+	if err = service.ValidateSearch(page("Machine_translation"), dePage("Computerlinguistik"), 2, SearchOptions{Strategy: ShortestStrategy}); err == nil {
+		t.Errorf("Expected an error for start and target on different sites")
+	}
+	if err = service.ValidateSearch(page("Machine_translation"), page("Linguistics"), 2, SearchOptions{Strategy: ShortestStrategy}); err == nil {
+		t.Errorf("Expected an error for start and target on another site than the service's")
+	}
+}
@@ -0,0 +1,16 @@
+-- MySQL dump 10.19  Distrib 10.3.38-MariaDB, for debian-linux-gnu (x86_64)
+--
+-- Table structure for table `linktarget`
+--
+
+DROP TABLE IF EXISTS `linktarget`;
+CREATE TABLE `linktarget` (
+  `lt_id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `lt_namespace` int(11) NOT NULL,
+  `lt_title` varbinary(255) NOT NULL,
+  PRIMARY KEY (`lt_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=binary;
+
+LOCK TABLES `linktarget` WRITE;
+INSERT INTO `linktarget` VALUES (1,0,'Computational_linguistics'),(2,0,'Noam_Chomsky'),(3,0,'Linguistics'),(4,0,'Machine_translation'),(5,4,'About');
+UNLOCK TABLES;
@@ -0,0 +1,29 @@
+-- MySQL dump 10.19  Distrib 10.3.38-MariaDB, for debian-linux-gnu (x86_64)
+--
+-- Host: db1206    Database: enwiki
+-- ------------------------------------------------------
+--
+-- Table structure for table `page`
+--
+
+DROP TABLE IF EXISTS `page`;
+CREATE TABLE `page` (
+  `page_id` int(8) unsigned NOT NULL AUTO_INCREMENT,
+  `page_namespace` int(11) NOT NULL DEFAULT 0,
+  `page_title` varbinary(255) NOT NULL DEFAULT '',
+  `page_is_redirect` tinyint(1) unsigned NOT NULL DEFAULT 0,
+  `page_is_new` tinyint(1) unsigned NOT NULL DEFAULT 0,
+  `page_random` double unsigned NOT NULL DEFAULT 0,
+  `page_touched` binary(14) NOT NULL,
+  `page_links_updated` varbinary(14) DEFAULT NULL,
+  `page_latest` int(8) unsigned NOT NULL DEFAULT 0,
+  `page_len` int(8) unsigned NOT NULL DEFAULT 0,
+  `page_content_model` varbinary(32) DEFAULT NULL,
+  `page_lang` varbinary(35) DEFAULT NULL,
+  PRIMARY KEY (`page_id`)
+) ENGINE=InnoDB AUTO_INCREMENT=76000000 DEFAULT CHARSET=binary;
+
+LOCK TABLES `page` WRITE;
+INSERT INTO `page` VALUES (10,0,'Machine_translation',0,0,0.123,'20240101000000','20240101000000',1001,5000,'wikitext',NULL),(20,0,'Computational_linguistics',0,0,0.456,'20240101000000','20240101000000',1002,4000,'wikitext',NULL),(30,0,'Noam_Chomsky',0,0,0.789,'20240101000000','20240101000000',1003,9000,'wikitext',NULL),(40,0,'Linguistics',0,0,0.321,'20240101000000','20240101000000',1004,8000,'wikitext',NULL);
+INSERT INTO `page` VALUES (50,0,'Chomsky\'s_hierarchy',0,0,0.654,'20240101000000','20240101000000',1005,3000,'wikitext',NULL),(60,1,'Machine_translation',0,0,0.987,'20240101000000','20240101000000',1006,200,'wikitext',NULL),(70,0,'C++',0,0,0.111,'20240101000000','20240101000000',1007,7000,'wikitext',NULL),(80,0,'MT',1,0,0.222,'20240101000000','20240101000000',1008,40,'wikitext',NULL),(90,0,'Chomsky',1,0,0.333,'20240101000000','20240101000000',1009,40,'wikitext',NULL);
+UNLOCK TABLES;
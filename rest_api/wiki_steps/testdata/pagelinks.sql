@@ -0,0 +1,18 @@
+-- MySQL dump 10.19  Distrib 10.3.38-MariaDB, for debian-linux-gnu (x86_64)
+--
+-- Table structure for table `pagelinks`
+--
+
+DROP TABLE IF EXISTS `pagelinks`;
+CREATE TABLE `pagelinks` (
+  `pl_from` int(8) unsigned NOT NULL DEFAULT 0,
+  `pl_namespace` int(11) NOT NULL DEFAULT 0,
+  `pl_title` varbinary(255) NOT NULL DEFAULT '',
+  `pl_from_namespace` int(11) NOT NULL DEFAULT 0,
+  PRIMARY KEY (`pl_from`,`pl_namespace`,`pl_title`)
+) ENGINE=InnoDB DEFAULT CHARSET=binary;
+
+LOCK TABLES `pagelinks` WRITE;
+INSERT INTO `pagelinks` VALUES (10,0,'Computational_linguistics',0),(10,0,'C++',0),(10,0,'Missing_page',0),(10,4,'About',0),(20,0,'Noam_Chomsky',0),(20,0,'Linguistics',0),(20,0,'Noam_Chomsky',0);
+INSERT INTO `pagelinks` VALUES (30,0,'Chomsky\'s_hierarchy',0),(30,0,'Linguistics',0),(40,0,'Machine_translation',0),(50,0,'Noam_Chomsky',0),(60,0,'Noam_Chomsky',1),(70,0,'C++',0),(10,0,'Chomsky',0),(50,0,'MT',0),(80,0,'Machine_translation',0),(90,0,'Noam_Chomsky',0);
+UNLOCK TABLES;
@@ -0,0 +1,16 @@
+-- MySQL dump 10.19  Distrib 10.3.38-MariaDB, for debian-linux-gnu (x86_64)
+--
+-- Table structure for table `pagelinks`
+--
+
+DROP TABLE IF EXISTS `pagelinks`;
+CREATE TABLE `pagelinks` (
+  `pl_from` int(8) unsigned NOT NULL DEFAULT 0,
+  `pl_from_namespace` int(11) NOT NULL DEFAULT 0,
+  `pl_target_id` bigint(20) unsigned NOT NULL,
+  PRIMARY KEY (`pl_from`,`pl_target_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=binary;
+
+LOCK TABLES `pagelinks` WRITE;
+INSERT INTO `pagelinks` VALUES (10,0,1),(10,0,5),(20,0,2),(20,0,3),(30,0,3),(40,0,4),(60,1,2);
+UNLOCK TABLES;
@@ -0,0 +1,18 @@
+-- MySQL dump 10.19  Distrib 10.3.38-MariaDB, for debian-linux-gnu (x86_64)
+--
+-- Table structure for table `redirect`
+--
+
+DROP TABLE IF EXISTS `redirect`;
+CREATE TABLE `redirect` (
+  `rd_from` int(8) unsigned NOT NULL DEFAULT 0,
+  `rd_namespace` int(11) NOT NULL DEFAULT 0,
+  `rd_title` varbinary(255) NOT NULL DEFAULT '',
+  `rd_interwiki` varbinary(32) DEFAULT NULL,
+  `rd_fragment` varbinary(255) DEFAULT NULL,
+  PRIMARY KEY (`rd_from`)
+) ENGINE=InnoDB DEFAULT CHARSET=binary;
+
+LOCK TABLES `redirect` WRITE;
+INSERT INTO `redirect` VALUES (80,0,'Machine_translation','',''),(90,0,'Noam_Chomsky',NULL,'Early_life'),(100,0,'Translation','fr','');
+UNLOCK TABLES;
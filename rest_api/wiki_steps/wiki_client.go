@@ -0,0 +1,186 @@
+package wikiSteps
+
+import (
+	"app/rest_api/logging"
+	"app/rest_api/util"
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const DefaultUserAgent string = "golearning-wikisteps/1.0 (https://github.com/danny-bernier/golearning)"
+
+// PolitenessPolicy controls how hard a WikiSteps instance is allowed to hit the wiki it searches
+type PolitenessPolicy struct {
+	MaxConcurrent     int           // requests in flight at once
+	RequestsPerSecond float64       // sustained request rate, 0 disables rate limiting
+	Burst             int           // requests allowed at once before the rate applies
+	MaxRetries        int           // retries of a request answered with a retryable status
+	InitialBackoff    time.Duration // delay before the first retry, doubled for every retry
+	MaxBackoff        time.Duration
+	UserAgent         string
+}
+
+func DefaultPolitenessPolicy() PolitenessPolicy {
+	return PolitenessPolicy{
+		MaxConcurrent:     10,
+		RequestsPerSecond: 50,
+		Burst:             10,
+		MaxRetries:        4,
+		InitialBackoff:    500 * time.Millisecond,
+		MaxBackoff:        30 * time.Second,
+		UserAgent:         DefaultUserAgent,
+	}
+}
+
+// httpStatusError is returned for responses with an error status, once retries ran out or
+// when the status is not retryable
+type httpStatusError struct {
+	StatusCode int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("status %d", e.StatusCode)
+}
+
+// wikiClient executes GET requests against a wiki while respecting a PolitenessPolicy
+type wikiClient struct {
+	log        logging.Logger
+	httpClient *http.Client
+	policy     PolitenessPolicy
+	sem        chan struct{} // semaphore for limiting the number of requests to the wiki
+	limiter    *util.TokenBucket
+}
+
+func newWikiClient(log logging.Logger, policy PolitenessPolicy) *wikiClient {
+	client := &wikiClient{
+		log:        log,
+		httpClient: &http.Client{},
+		policy:     policy,
+		sem:        make(chan struct{}, max(1, policy.MaxConcurrent)),
+	}
+	if policy.RequestsPerSecond > 0 {
+		client.limiter = util.NewTokenBucket(policy.RequestsPerSecond, max(1, policy.Burst))
+	}
+	return client
+}
+
+// callWikipedia retries requests answered with a retryable status, waiting for the Retry-After
+// header when present and an exponential backoff with jitter otherwise. Error statuses are
+// returned as an *httpStatusError, the body of an error page is never returned.
+func (c *wikiClient) callWikipedia(ctx context.Context, workerName string, url string) (io.ReadCloser, error) {
+	log := c.log.With("worker", workerName, "url", url)
+	for attempt := 0; ; attempt += 1 {
+		resp, err := c.doRequest(ctx, log, workerName, url)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isRetryableStatus(resp.StatusCode) {
+			if resp.StatusCode >= http.StatusBadRequest {
+				resp.Body.Close()
+				return nil, fmt.Errorf("worker %s's GET request for URL %s failed; %w", workerName, url, &httpStatusError{resp.StatusCode})
+			}
+			if resp.Body == nil {
+				return nil, fmt.Errorf("worker %s's response body is nil for URL %s", workerName, url)
+			}
+			log.Trace("Worker's GET request returned a body", "bytes", resp.ContentLength)
+			return resp.Body, nil
+		}
+		resp.Body.Close()
+
+		if attempt >= c.policy.MaxRetries {
+			return nil, fmt.Errorf("worker %s's GET request for URL %s failed after %d attempts; %w", workerName, url, attempt+1, &httpStatusError{resp.StatusCode})
+		}
+
+		delay := c.retryDelay(resp, attempt)
+		log.Debug("Worker's GET request returned a retryable status, retrying...", "status", resp.StatusCode, "delay", delay.String())
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// probe executes a single GET request, without retries, and expects a successful status
+func (c *wikiClient) probe(ctx context.Context, url string) error {
+	resp, err := c.doRequest(ctx, c.log.With("worker", "probe", "url", url), "probe", url)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("GET request for URL %s returned status %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *wikiClient) doRequest(ctx context.Context, log logging.Logger, workerName string, url string) (*http.Response, error) {
+	log.Debug("Worker is waiting for semaphore aquisition...")
+	waitStart := time.Now()
+	select {
+	case c.sem <- struct{}{}:
+		semaphoreWait.ObserveDuration(time.Since(waitStart))
+		log.Debug("Worker aquired semaphore.")
+		defer func() { <-c.sem }()
+	case <-ctx.Done():
+		log.Debug("Worker recieved exit signal while waiting for semaphore aquisition.")
+		return nil, ctx.Err()
+	}
+
+	if c.limiter != nil {
+		log.Trace("Worker is waiting for the rate limiter...")
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Trace("Worker is building GET request")
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("worker %s encountered an error when building GET request for URL: %s; %w", workerName, url, err)
+	}
+	req.Header.Set("User-Agent", c.policy.UserAgent)
+
+	log.Debug("Worker is executing a GET request")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		httpResponses.WithLabelValues("error").Inc()
+		return nil, fmt.Errorf("worker %s encountered an error when executing GET for URL %s; %w", workerName, url, err)
+	}
+	httpResponses.WithLabelValues(strconv.Itoa(resp.StatusCode)).Inc()
+	return resp, nil
+}
+
+func (c *wikiClient) retryDelay(resp *http.Response, attempt int) time.Duration {
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return min(c.policy.MaxBackoff, time.Duration(seconds)*time.Second)
+		}
+		if at, err := http.ParseTime(retryAfter); err == nil {
+			return min(c.policy.MaxBackoff, max(0, time.Until(at)))
+		}
+	}
+
+	backoff := min(c.policy.MaxBackoff, c.policy.InitialBackoff<<attempt)
+	if backoff <= 0 {
+		return 0
+	}
+	return backoff/2 + rand.N(backoff/2+1) // jitter between half and the full backoff
+}
+
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
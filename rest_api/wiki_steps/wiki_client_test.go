@@ -0,0 +1,80 @@
+package wikiSteps
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWikiClientRetries(t *testing.T) {
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != DefaultUserAgent {
+			t.Errorf("Expected: %s Actual: %s", DefaultUserAgent, r.Header.Get("User-Agent"))
+		}
+		switch calls.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			io.WriteString(w, "ok")
+		}
+	}))
+	defer server.Close()
+
+	policy := DefaultPolitenessPolicy()
+	policy.InitialBackoff = time.Millisecond
+	client := newWikiClient(nopLogger{}, policy)
+
+	body, err := client.callWikipedia(context.Background(), "test", server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer body.Close()
+
+	if content, _ := io.ReadAll(body); string(content) != "ok" || calls.Load() != 3 {
+		t.Errorf("Expected: 'ok' after 3 calls Actual: '%s' after %d calls", content, calls.Load())
+	}
+
+	policy.MaxRetries = 0
+	calls.Store(0)
+	var statusErr *httpStatusError
+	if _, err = newWikiClient(nopLogger{}, policy).callWikipedia(context.Background(), "test", server.URL); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected: an error with status 429 when retries are exhausted Actual: %v", err)
+	}
+}
+
+func TestWikiClientErrorStatus(t *testing.T) {
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `<a href="/wiki/Error_page_link">Error page link</a>`)
+	}))
+	defer server.Close()
+
+	// error statuses that are not retryable fail right away instead of returning the error page
+	var statusErr *httpStatusError
+	body, err := newWikiClient(nopLogger{}, DefaultPolitenessPolicy()).callWikipedia(context.Background(), "test", server.URL)
+	if body != nil || !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound || calls.Load() != 1 {
+		t.Errorf("Expected: an error with status 404 after 1 call Actual: %v after %d calls", err, calls.Load())
+	}
+
+	// the html source reads a missing article as an article without links, which was not fetched
+	site, err := NewWikiSite(server.URL, "/wiki/", WikipediaApiPath, true, nil, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	fetched := pagesFetched.Value()
+	links, err := NewHttpLinkSource(nopLogger{}, site, DefaultPolitenessPolicy()).Links(context.Background(), "test", site.PageUrl("Deleted"))
+	if err != nil || len(links) != 0 || pagesFetched.Value() != fetched {
+		t.Errorf("Expected: no links and no fetched page Actual: %v (%v) with %v fetched pages", links, err, pagesFetched.Value()-fetched)
+	}
+}